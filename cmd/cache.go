@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sgaunet/gitlab-issue-report/internal/core"
+	"github.com/spf13/cobra"
+)
+
+// defaultPruneAge is the default age of the cache entries removed by cache prune.
+const defaultPruneAge = 30 * 24 * time.Hour
+
+var errPruneAgeNegative = errors.New("--older-than must not be negative")
+
+// pruneAge holds the value of the --older-than flag of cache prune.
+var pruneAge time.Duration
+
+// cacheCmd represents the cache command.
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clean the local issue cache",
+	Long: `Inspect and clean the local issue cache.
+
+The project and group commands keep the issues they fetch in a local cache, one
+file per project or group. Later runs only download the issues updated since the
+last sync. The cache is stored in --cache-dir, by default in gitlab-issue-report
+under the user cache directory.
+
+EXAMPLES:
+  # Cached projects and groups
+  gitlab-issue-report cache stats
+
+  # Remove the entries not synced for a week
+  gitlab-issue-report cache prune --older-than 168h
+
+  # Remove every entry
+  gitlab-issue-report cache clear`,
+}
+
+// cacheStatsCmd represents the cache stats command.
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "List the cached projects and groups",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cache, err := openCache(&opts)
+		if err != nil {
+			return err
+		}
+		entries, err := cache.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to list cache entries: %w", err)
+		}
+		return writeCacheStats(cmd.OutOrStdout(), cache.Dir(), entries)
+	},
+}
+
+// cachePruneCmd represents the cache prune command.
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the cache entries not synced recently",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if pruneAge < 0 {
+			return fmt.Errorf("%w (got %v)", errPruneAgeNegative, pruneAge)
+		}
+		cache, err := openCache(&opts)
+		if err != nil {
+			return err
+		}
+		removed, err := cache.Prune(pruneAge, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to prune cache: %w", err)
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cache entries\n", len(removed)); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	},
+}
+
+// cacheClearCmd represents the cache clear command.
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cache entry",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cache, err := openCache(&opts)
+		if err != nil {
+			return err
+		}
+		removed, err := cache.Clear()
+		if err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cache entries\n", len(removed)); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	},
+}
+
+// writeCacheStats writes one line per cache entry, aligned in columns, followed by the totals.
+func writeCacheStats(w io.Writer, dir string, entries []core.CacheEntry) error {
+	var (
+		b      strings.Builder
+		issues int
+		size   int64
+	)
+	b.WriteString("Cache directory: " + dir + "\n")
+	if len(entries) > 0 {
+		b.WriteString("\nHOST\tACCOUNT\tSCOPE\tID\tISSUES\tSIZE\tLAST SYNC\n")
+	}
+	for _, entry := range entries {
+		issues += entry.Issues
+		size += entry.Size
+		b.WriteString(strings.Join([]string{
+			entry.Host,
+			cmp.Or(entry.Account, "-"),
+			entry.Scope,
+			strconv.FormatInt(entry.ID, 10),
+			strconv.Itoa(entry.Issues),
+			formatSize(entry.Size),
+			entry.SyncedAt.Local().Format(time.DateTime),
+		}, "\t") + "\n")
+	}
+	fmt.Fprintf(&b, "\nTotal: %d entries, %d issues, %s\n", len(entries), issues, formatSize(size))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := io.WriteString(tw, b.String()); err != nil {
+		return fmt.Errorf("failed to write cache stats: %w", err)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write cache stats: %w", err)
+	}
+	return nil
+}
+
+// formatSize formats a size in bytes with a binary unit.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exp])
+}
+
+func init() {
+	cachePruneCmd.Flags().DurationVar(&pruneAge, "older-than", defaultPruneAge,
+		"Remove the entries not synced for this long (e.g., 168h)")
+
+	cacheCmd.AddCommand(cacheStatsCmd, cachePruneCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
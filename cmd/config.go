@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/sgaunet/gitlab-issue-report/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var errConfigNotFound = errors.New("config file not found")
+
+// applyProfile loads the configuration file and applies the profile selected with
+// --profile, GITLAB_PROFILE or default_profile. The profile only fills the environment
+// variables left unset and the flags left unchanged, so that flags take precedence over
+// environment variables, which take precedence over the profile, itself taking
+// precedence over built-in defaults. The token settings of the profile are kept in
+// o.tokenProfile for setupEnvironment. It returns the name of the applied profile,
+// empty when none applies.
+//
+// A profile is tied to the instance of its uri, its token, TLS settings and group are
+// only used on this instance. When GITLAB_URI (gitlab.com when unset) is another
+// instance, a profile selected with --profile, or with GITLAB_PROFILE or default_profile
+// while GITLAB_URI is not set, replaces GITLAB_URI, and the token of the environment,
+// meant for the other instance, is ignored. A profile selected with GITLAB_PROFILE or
+// default_profile while GITLAB_URI is set keeps GITLAB_URI, without its token, TLS
+// settings and group.
+func applyProfile(o *commandOptions, cmd *cobra.Command) (string, error) {
+	name := o.profile
+	if name == "" {
+		name = os.Getenv("GITLAB_PROFILE")
+	}
+
+	cfg, err := loadConfig(o)
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil {
+		if name != "" {
+			return "", fmt.Errorf("%w, cannot select profile %q", errConfigNotFound, name)
+		}
+		return "", nil
+	}
+	profile, err := cfg.Profile(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to select profile: %w", err)
+	}
+	if profile == nil {
+		return "", nil
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+
+	onInstance := true
+	switch {
+	case profile.URI == "" || sameInstance(gitlabURIOrDefault(), profile.URI):
+	case o.profile != "" || os.Getenv("GITLAB_URI") == "":
+		logrus.Debugf("Profile %q is on %s, ignoring GITLAB_URI and the token of the environment",
+			name, profile.URI)
+		if err := os.Setenv("GITLAB_URI", profile.URI); err != nil {
+			return "", fmt.Errorf("failed to set GITLAB_URI: %w", err)
+		}
+		o.ignoreEnvToken = true
+	default:
+		logrus.Debugf("Profile %q is on %s, not on GITLAB_URI: its token, TLS settings and group are not used",
+			name, profile.URI)
+		onInstance = false
+	}
+	setEnvDefault("GITLAB_URI", profile.URI)
+	if profile.Timeout > 0 {
+		setEnvDefault("GITLAB_API_TIMEOUT", profile.Timeout.String())
+	}
+	setEnvDefault("GITLAB_TIMEZONE", profile.Timezone)
+
+	if onInstance {
+		o.tokenProfile = profile
+		applyProfileTLS(o, cmd, profile)
+	}
+	if flag := cmd.Flags().Lookup("format"); flag != nil && !flag.Changed && profile.Format != "" {
+		o.formatOutput = profile.Format
+	}
+	// Only the group command has --group-id, snapshot save must not switch to the group
+	if cmd.Flags().Lookup("group-id") != nil && o.groupRef == "" && onInstance {
+		o.groupRef = profile.Group
+	}
+	return name, nil
+}
+
+// applyProfileTLS sets the TLS and proxy settings of the profile whose flag was not given.
+func applyProfileTLS(o *commandOptions, cmd *cobra.Command, profile *config.Profile) {
+	settings := []struct {
+		flag   string
+		target *string
+		value  string
+	}{
+		{"ca-cert", &o.caCert, profile.CACert},
+		{"client-cert", &o.clientCert, profile.ClientCert},
+		{"client-key", &o.clientKey, profile.ClientKey},
+		{"proxy", &o.proxy, profile.Proxy},
+	}
+	for _, setting := range settings {
+		if setting.value != "" && !cmd.Flags().Changed(setting.flag) {
+			*setting.target = setting.value
+		}
+	}
+	if profile.InsecureSkipVerify && !cmd.Flags().Changed("insecure-skip-verify") {
+		o.insecureSkipVerify = true
+	}
+}
+
+// resolveToken returns the GitLab access token and where it was found, trying in order
+// GITLAB_TOKEN, the file named by GITLAB_TOKEN_FILE, the token, token_file or
+// token_command of the profile, then the token saved by the glab CLI for the host of
+// GITLAB_URI. The environment variables are skipped with ignoreEnv. The token is empty
+// when no source has one. A source that is set but cannot be read is an error rather
+// than skipped, so that a typo does not silently select another account.
+func resolveToken(ctx context.Context, profile *config.Profile, ignoreEnv bool) (token, source string, err error) {
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" && !ignoreEnv {
+		return token, "GITLAB_TOKEN", nil
+	}
+	if path := os.Getenv("GITLAB_TOKEN_FILE"); path != "" && !ignoreEnv {
+		token, err := config.ReadTokenFile(path)
+		if err != nil {
+			return "", "", fmt.Errorf("GITLAB_TOKEN_FILE: %w", err)
+		}
+		return token, "GITLAB_TOKEN_FILE " + path, nil
+	}
+	if profile != nil {
+		switch {
+		case profile.Token != "":
+			return profile.Token, "profile token", nil
+		case profile.TokenFile != "":
+			token, err := config.ReadTokenFile(profile.TokenFile)
+			if err != nil {
+				return "", "", fmt.Errorf("profile token_file: %w", err)
+			}
+			return token, "profile token_file " + profile.TokenFile, nil
+		case profile.TokenCommand != "":
+			token, err := config.RunTokenCommand(ctx, profile.TokenCommand)
+			if err != nil {
+				return "", "", fmt.Errorf("profile token_command: %w", err)
+			}
+			return token, "profile token_command", nil
+		}
+	}
+	return glabToken()
+}
+
+// glabToken returns the token saved by the glab CLI for the host of GITLAB_URI, empty
+// when glab is not configured for this host.
+func glabToken() (token, source string, err error) {
+	uri, err := url.Parse(os.Getenv("GITLAB_URI"))
+	if err != nil || uri.Host == "" {
+		return "", "", nil
+	}
+	path, err := config.GlabConfigPath()
+	if err != nil {
+		logrus.Debugf("No glab config: %v", err)
+		return "", "", nil
+	}
+	for _, host := range []string{uri.Host, uri.Hostname()} {
+		token, err := config.GlabToken(path, host)
+		if err != nil {
+			return "", "", err
+		}
+		if token != "" {
+			return token, "glab config " + path, nil
+		}
+	}
+	return "", "", nil
+}
+
+// loadConfig reads the configuration file given with --config, or the default one.
+// It returns nil without error when there is no config file at the default location.
+func loadConfig(o *commandOptions) (*config.Config, error) {
+	path := o.configFile
+	if path == "" {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			logrus.Debugf("No config file: %v", err)
+			return nil, nil
+		}
+		path = defaultPath
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// gitlabURIOrDefault returns GITLAB_URI, https://gitlab.com when it is not set.
+func gitlabURIOrDefault() string {
+	if uri := os.Getenv("GITLAB_URI"); uri != "" {
+		return uri
+	}
+	return "https://gitlab.com"
+}
+
+// sameInstance reports whether the URIs a and b are the same GitLab instance: same host,
+// port and path, whatever the case of the host and a trailing slash.
+func sameInstance(a, b string) bool {
+	urlA, errA := url.Parse(a)
+	urlB, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(urlA.Host, urlB.Host) &&
+		strings.TrimSuffix(urlA.Path, "/") == strings.TrimSuffix(urlB.Path, "/")
+}
+
+// setEnvDefault sets the environment variable to value, unless it is already set or
+// value is empty.
+func setEnvDefault(key, value string) {
+	if value != "" && os.Getenv(key) == "" {
+		_ = os.Setenv(key, value)
+	}
+}
@@ -3,8 +3,12 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/sgaunet/gitlab-issue-report/internal/core"
+	"github.com/sgaunet/gitlab-issue-report/internal/render"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,8 +19,44 @@ var (
 	errIntervalRequired       = errors.New("--created or --updated requires --interval to be set")
 	errCreatedUpdatedConflict = errors.New("--created and --updated cannot be used together")
 	errInvalidTimezoneValue   = errors.New("invalid --timezone value")
+	errInvalidColumnsValue    = errors.New("invalid --columns value")
+	errColumnsNotSupported    = errors.New("--columns is not supported by this output format")
+	errInvalidSortValue       = errors.New("invalid --sort value")
+	errSortNotSupported       = errors.New("--sort is not supported by streaming output formats")
+	errInvalidGroupByValue    = errors.New("invalid --group-by value")
+	errGroupByNotSupported    = errors.New("--group-by is not supported by this output format")
+	errTemplateRequired       = errors.New("--format template requires --template or --template-string")
+	errTemplateConflict       = errors.New("--template and --template-string cannot be used together")
+	errTemplateWithoutFormat  = errors.New("--template and --template-string require --format template")
+	errSummaryNotSupported    = errors.New("--summary is not supported by this output format")
+	errAssigneeMineConflict   = errors.New("--assignee and --mine cannot be used together")
+	errConcurrencyNegative    = errors.New("--concurrency must not be negative")
+	errRetryNegative          = errors.New("--max-retries and --retry-wait must not be negative")
+	errDeadlineNegative       = errors.New("--deadline must not be negative")
+	errRefreshWithoutCache    = errors.New("--refresh and --no-cache cannot be used together")
+	errOfflineMine            = errors.New("--mine is not available with --offline, use --assignee instead")
+	errInvalidScopeValue      = errors.New("invalid --scope value")
 )
 
+// validFormats lists the values accepted by --format.
+var validFormats = []string{"plain", "table", "markdown", "json", "ndjson", "csv", "tsv", "html", "template"}
+
+// formatFlagUsage returns the help text of the --format flag.
+func formatFlagUsage() string {
+	return "Output format: " + strings.Join(validFormats, ", ")
+}
+
+// columnsFlagUsage returns the help text of the --columns flag.
+func columnsFlagUsage() string {
+	return "Columns to display, comma-separated (" + strings.Join(render.ColumnNames(), ", ") + ")"
+}
+
+// sortFlagUsage returns the help text of the --sort flag.
+func sortFlagUsage() string {
+	return "Sort keys, comma-separated, each optionally suffixed with :asc or :desc (" +
+		strings.Join(render.SortFields(), ", ") + ")"
+}
+
 // reconcileFlags processes flag values and applies flag priority logic.
 func reconcileFlags(o *commandOptions) error {
 	// Reconcile logging flags - debug/verbose take precedence over log-level
@@ -37,13 +77,49 @@ func validateFlags(o *commandOptions) error {
 	if err := validateFormatFlag(o); err != nil {
 		return err
 	}
+	if o.scope != "" && !slices.Contains(core.Scopes, o.scope) {
+		return fmt.Errorf("%w: %s (must be one of %s)", errInvalidScopeValue, o.scope, strings.Join(core.Scopes, ", "))
+	}
 	if err := validateDateFilters(o); err != nil {
 		return err
 	}
+	if o.assignee != "" && o.mineOption {
+		return errAssigneeMineConflict
+	}
 	if err := validateAPITimeout(o); err != nil {
 		return err
 	}
-	return validateTimezone(o)
+	if o.concurrency < 0 {
+		return fmt.Errorf("%w (got %d)", errConcurrencyNegative, o.concurrency)
+	}
+	if o.maxRetries < 0 || o.retryWait < 0 {
+		return fmt.Errorf("%w (got %d, %v)", errRetryNegative, o.maxRetries, o.retryWait)
+	}
+	if o.deadline < 0 {
+		return fmt.Errorf("%w (got %v)", errDeadlineNegative, o.deadline)
+	}
+	if o.refresh && o.noCache {
+		return errRefreshWithoutCache
+	}
+	if o.offline && o.mineOption {
+		return errOfflineMine
+	}
+	if err := validateColumnsFlag(o); err != nil {
+		return err
+	}
+	if err := validateSortFlag(o); err != nil {
+		return err
+	}
+	if err := validateGroupByFlag(o); err != nil {
+		return err
+	}
+	if err := validateSummaryFlags(o); err != nil {
+		return err
+	}
+	if err := validateTimezone(o); err != nil {
+		return err
+	}
+	return validateTemplateFlags(o)
 }
 
 // validateStateFlag validates the state filter value.
@@ -57,13 +133,89 @@ func validateStateFlag(o *commandOptions) error {
 
 // validateFormatFlag validates the format output value.
 func validateFormatFlag(o *commandOptions) error {
-	if o.formatOutput != "plain" && o.formatOutput != "table" &&
-		o.formatOutput != "markdown" {
-		return fmt.Errorf("%w: %s (must be plain, table, or markdown)", errInvalidFormatValue, o.formatOutput)
+	if !slices.Contains(validFormats, o.formatOutput) {
+		return fmt.Errorf("%w: %s (must be one of %s)", errInvalidFormatValue, o.formatOutput,
+			strings.Join(validFormats, ", "))
 	}
 	return nil
 }
 
+// validateColumnsFlag validates the selected columns against the column registry
+// and the output format.
+func validateColumnsFlag(o *commandOptions) error {
+	if len(o.columns) == 0 {
+		return nil
+	}
+	if _, err := render.ParseColumns(o.columns); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidColumnsValue, err)
+	}
+	if _, ok := newFormatRenderer(o.formatOutput).(render.ColumnSetter); !ok {
+		return fmt.Errorf("%w: --format %s", errColumnsNotSupported, o.formatOutput)
+	}
+	return nil
+}
+
+// validateSortFlag validates the sort keys. Streaming formats write issues before
+// the complete list is known and cannot be sorted.
+func validateSortFlag(o *commandOptions) error {
+	if len(o.sortKeys) == 0 {
+		return nil
+	}
+	if _, err := render.ParseSortKeys(o.sortKeys); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidSortValue, err)
+	}
+	if isStreamingFormat(o.formatOutput) {
+		return fmt.Errorf("%w: --format %s", errSortNotSupported, o.formatOutput)
+	}
+	return nil
+}
+
+// validateGroupByFlag validates the group-by field and the output format.
+func validateGroupByFlag(o *commandOptions) error {
+	if o.groupBy == "" {
+		return nil
+	}
+	if _, err := render.ParseGroupBy(o.groupBy); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidGroupByValue, err)
+	}
+	if _, ok := newFormatRenderer(o.formatOutput).(render.GroupSetter); !ok {
+		return fmt.Errorf("%w: --format %s", errGroupByNotSupported, o.formatOutput)
+	}
+	return nil
+}
+
+// validateSummaryFlags validates that the output format can print a summary.
+// Templates access the summary through .Summary instead.
+func validateSummaryFlags(o *commandOptions) error {
+	if !o.summary && !o.summaryOnly {
+		return nil
+	}
+	if _, ok := newFormatRenderer(o.formatOutput).(render.SummarySetter); !ok {
+		return fmt.Errorf("%w: --format %s (use .Summary in the template)", errSummaryNotSupported, o.formatOutput)
+	}
+	return nil
+}
+
+// validateTemplateFlags validates the template flags and parses the template,
+// so syntax errors are reported before any API call.
+func validateTemplateFlags(o *commandOptions) error {
+	hasTemplate := o.templateFile != "" || o.templateText != ""
+	if o.formatOutput != "template" {
+		if hasTemplate {
+			return errTemplateWithoutFormat
+		}
+		return nil
+	}
+	if !hasTemplate {
+		return errTemplateRequired
+	}
+	if o.templateFile != "" && o.templateText != "" {
+		return errTemplateConflict
+	}
+	_, err := newTemplateRenderer(o)
+	return err
+}
+
 // validateDateFilters validates date filter combinations.
 func validateDateFilters(o *commandOptions) error {
 	if (o.createdFilter || o.updatedFilter) && o.interval == "" {
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	errInvalidGitFile    = errors.New("invalid .git file, expected \"gitdir: <path>\"")
+	errGitConfigIncludes = errors.New("too many nested includes in git config")
+	errNoGitRemote       = errors.New("git repository has no remote")
+)
+
+// maxGitConfigDepth is the include depth git itself allows.
+const maxGitConfigDepth = 10
+
+// gitRepository holds the git directories of a working tree.
+type gitRepository struct {
+	gitDir    string // Git directory of the working tree, .git/worktrees/<name> for a worktree
+	commonDir string // Directory shared by the worktrees, holding the config and the remotes
+}
+
+// findGitRepository locates the git repository of the current directory like git does:
+// $GIT_DIR when set, otherwise the first .git directory or .git file of the current
+// directory, or of $GIT_WORK_TREE when set, and their parents. A .git file, as found in
+// worktrees and submodules, points to the actual git directory.
+func findGitRepository() (gitRepository, error) {
+	if dir := os.Getenv("GIT_DIR"); dir != "" {
+		gitDir, err := resolveGitDir(dir)
+		if err != nil {
+			return gitRepository{}, err
+		}
+		return newGitRepository(gitDir), nil
+	}
+
+	cwd := os.Getenv("GIT_WORK_TREE")
+	if cwd == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return gitRepository{}, fmt.Errorf("%w: %w", errGitRepositoryNotFound, err)
+		}
+		cwd = dir
+	}
+	cwd, err := filepath.Abs(cwd)
+	if err != nil {
+		return gitRepository{}, fmt.Errorf("%w: %w", errGitRepositoryNotFound, err)
+	}
+	for {
+		logrus.Debugln(cwd)
+		candidate := filepath.Join(cwd, ".git")
+		if _, err := os.Stat(candidate); err == nil {
+			gitDir, err := resolveGitDir(candidate)
+			if err != nil {
+				return gitRepository{}, err
+			}
+			return newGitRepository(gitDir), nil
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			return gitRepository{}, fmt.Errorf("%w", errGitRepositoryNotFound)
+		}
+		cwd = parent
+	}
+}
+
+// newGitRepository returns the repository of gitDir, whose commondir file, present in
+// the git directories of worktrees, gives the directory shared with the main working
+// tree. $GIT_COMMON_DIR takes precedence, as for git.
+func newGitRepository(gitDir string) gitRepository {
+	repo := gitRepository{gitDir: gitDir, commonDir: gitDir}
+	if dir := os.Getenv("GIT_COMMON_DIR"); dir != "" {
+		repo.commonDir = dir
+	} else if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		repo.commonDir = strings.TrimSpace(string(data))
+	}
+	if !filepath.IsAbs(repo.commonDir) {
+		repo.commonDir = filepath.Join(gitDir, repo.commonDir)
+	}
+	logrus.Debugf("Git directory %s, common directory %s", repo.gitDir, repo.commonDir)
+	return repo
+}
+
+// resolveGitDir returns the git directory at path: path itself when it is a directory,
+// otherwise the directory named by the "gitdir: <path>" line of the file, relative to
+// the directory holding the file.
+func resolveGitDir(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errGitRepositoryNotFound, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errGitRepositoryNotFound, err)
+	}
+	if info.IsDir() {
+		return path, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errInvalidGitFile, path)
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return target, nil
+}
+
+// getRemoteURLs returns the URLs of the remotes of the repository by remote name.
+func getRemoteURLs(repo gitRepository) (map[string]string, error) {
+	values, err := readGitConfig(repo)
+	if err != nil {
+		return nil, err
+	}
+	remotes := make(map[string]string)
+	for key, value := range values {
+		name, isRemote := strings.CutPrefix(key, "remote.")
+		name, isURL := strings.CutSuffix(name, ".url")
+		if isRemote && isURL && name != "" {
+			logrus.Debugf("Remote %s: %s", name, value)
+			remotes[name] = value
+		}
+	}
+	if len(remotes) == 0 {
+		return nil, errNoGitRemote
+	}
+	return remotes, nil
+}
+
+// readGitConfig returns the settings of the config file of the repository by their
+// full name, e.g. "remote.origin.url", the last value of a setting winning as for git.
+// The files of include and includeIf sections are read where they appear; the gitdir
+// and onbranch conditions are supported.
+func readGitConfig(repo gitRepository) (map[string]string, error) {
+	values := make(map[string]string)
+	reader := gitConfigReader{repo: repo, values: values}
+	if err := reader.read(filepath.Join(repo.commonDir, "config"), 0); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// gitConfigReader reads a git config file and the files it includes.
+type gitConfigReader struct {
+	repo   gitRepository
+	values map[string]string
+}
+
+// gitConfigSection matches a section header: [section], [section "subsection"] or the
+// deprecated [section.subsection].
+var gitConfigSection = regexp.MustCompile(`^\[\s*([^\s\]"]+)(?:\s+"((?:[^"\\]|\\.)*)")?\s*\]`)
+
+// read reads the config file at path, depth being the number of includes leading to it.
+func (r *gitConfigReader) read(path string, depth int) error {
+	if depth > maxGitConfigDepth {
+		return fmt.Errorf("%w: %s", errGitConfigIncludes, path)
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && depth > 0 {
+		return nil // Missing included files are ignored by git
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read git config file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if match := gitConfigSection.FindStringSubmatch(line); match != nil {
+			section = strings.ToLower(match[1])
+			if match[2] != "" {
+				section += "." + strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(match[2])
+			}
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !hasValue {
+			value = "true"
+		}
+		value = parseGitConfigValue(value)
+
+		name := section + "." + key
+		switch {
+		case name == "include.path":
+			err = r.include(path, value, depth)
+		case strings.HasPrefix(section, "includeif.") && key == "path":
+			if r.matches(path, strings.TrimPrefix(section, "includeif.")) {
+				err = r.include(path, value, depth)
+			}
+		default:
+			r.values[name] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read git config file: %w", err)
+	}
+	return nil
+}
+
+// include reads the file included by the config file at from, relative paths being
+// relative to the directory of from.
+func (r *gitConfigReader) include(from, path string, depth int) error {
+	path = expandHome(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(from), path)
+	}
+	return r.read(path, depth+1)
+}
+
+// matches reports whether the repository meets the condition of an includeIf section
+// of the config file at from.
+func (r *gitConfigReader) matches(from, condition string) bool {
+	kind, pattern, _ := strings.Cut(condition, ":")
+	switch kind {
+	case "gitdir", "gitdir/i":
+		if strings.HasPrefix(pattern, "./") {
+			pattern = filepath.Join(filepath.Dir(from), pattern[2:])
+		}
+		pattern = expandHome(pattern)
+		if !filepath.IsAbs(pattern) {
+			pattern = "**/" + pattern
+		}
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+		gitDir := filepath.ToSlash(r.repo.gitDir)
+		pattern = filepath.ToSlash(pattern)
+		if kind == "gitdir/i" {
+			gitDir, pattern = strings.ToLower(gitDir), strings.ToLower(pattern)
+		}
+		return matchGitPattern(pattern, gitDir) || matchGitPattern(pattern, gitDir+"/")
+	case "onbranch":
+		head, err := os.ReadFile(filepath.Join(r.repo.gitDir, "HEAD"))
+		if err != nil {
+			return false
+		}
+		branch, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: refs/heads/")
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+		return ok && matchGitPattern(pattern, branch)
+	default:
+		logrus.Debugf("Ignoring unsupported includeIf condition %q", condition)
+		return false
+	}
+}
+
+// parseGitConfigValue returns the value of a setting without its quotes, escapes and
+// trailing comment.
+func parseGitConfigValue(raw string) string {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == '\\' && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(raw[i])
+			}
+		case (c == '#' || c == ';') && !quoted:
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// matchGitPattern reports whether name matches the wildcard pattern of git, where *
+// matches within a path element and ** across elements.
+func matchGitPattern(pattern, name string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	re, err := regexp.Compile(expr.String())
+	return err == nil && re.MatchString(name)
+}
+
+// expandHome replaces a leading ~/ with the home directory of the user.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
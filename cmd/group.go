@@ -4,13 +4,13 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/sgaunet/gitlab-issue-report/internal/render"
+	"github.com/sgaunet/gitlab-issue-report/internal/core"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var (
-	errGroupIDRequired = errors.New("group ID is required")
+	errGroupIDRequired = errors.New("group ID or path is required")
 )
 
 // groupCmd represents the group command.
@@ -34,48 +34,67 @@ EXAMPLES:
   # Only issues assigned to you
   gitlab-issue-report group -g 678 --mine`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		init, err := initIssueCommand(&opts, cmd)
-		if err != nil {
-			return err
-		}
+		return runGroupReport(cmd)
+	},
+}
 
-		// Check if group ID is provided
-		if opts.groupIDFlag == 0 {
-			if err := cmd.Help(); err != nil {
-				logrus.Warnf("Failed to display help: %v", err)
-			}
-			return errGroupIDRequired
-		}
+// runGroupReport reports on the issues of the group selected by the flags.
+func runGroupReport(cmd *cobra.Command) error {
+	init, err := initIssueCommand(&opts, cmd)
+	if err != nil {
+		return err
+	}
+	defer init.cancel()
 
-		// Build issue retrieval options
-		options, err := buildIssueOptions(&opts, 0, opts.groupIDFlag, init.beginTime, init.endTime)
-		if err != nil {
-			return err
+	// Check if group ID is provided
+	if opts.groupRef == "" {
+		if err := cmd.Help(); err != nil {
+			logrus.Warnf("Failed to display help: %v", err)
 		}
+		return errGroupIDRequired
+	}
 
-		// Get and display issues
-		issues, err := init.app.GetIssues(options...)
-		if err != nil {
-			return fmt.Errorf("failed to get issues: %w", err)
-		}
+	groupID, err := init.app.ResolveGroup(init.ctx, opts.groupRef)
+	if err != nil {
+		return fmt.Errorf("invalid group: %w", err)
+	}
 
-		// Fetch group path
-		groupPath, err := init.app.GetGroupPath(opts.groupIDFlag)
-		if err != nil {
-			logrus.Warnf("Failed to fetch group path: %v", err)
-			groupPath = fmt.Sprintf("ID:%d", opts.groupIDFlag)
-		}
+	// Build issue retrieval options
+	options, err := buildIssueOptions(init.ctx, &opts, 0, groupID, init.beginTime, init.endTime)
+	if err != nil {
+		return err
+	}
 
-		// Fetch project paths for all issues
-		projectMap, err := init.app.GetProjectPathsForIssues(issues)
-		if err != nil {
-			logrus.Warnf("Failed to fetch project paths: %v", err)
-			// Fall back to rendering without context
-			return renderIssues(issues, opts.formatOutput)
-		}
+	// Fetch group path
+	groupPath, err := init.app.GetGroupPath(init.ctx, groupID)
+	if err != nil {
+		logrus.Warnf("Failed to fetch group path: %v", err)
+		groupPath = fmt.Sprintf("ID:%d", groupID)
+	}
 
-		// Create context and render
-		context := render.NewGroupContext(groupPath, projectMap)
-		return renderIssuesWithContext(issues, context, opts.formatOutput)
-	},
+	// Project paths are listed once for the whole group
+	context := newGroupContext(init.ctx, init.app, groupID, groupPath)
+	context.Query = buildQueryParams(&opts, init.beginTime, init.endTime)
+
+	// Streaming formats write each page as soon as it is fetched,
+	// resolving paths of projects outside the group listing as they show up.
+	if isStreamingFormat(opts.formatOutput) {
+		return streamIssuesWithContext(init.ctx, init.app, [][]core.GetIssuesOption{options}, context, &opts)
+	}
+
+	// Get and display issues. When interrupted, the issues fetched so far are reported.
+	issues, fetchErr := init.app.GetIssues(init.ctx, options...)
+	if fetchErr != nil && init.ctx.Err() == nil {
+		return fmt.Errorf("failed to get issues: %w", fetchErr)
+	}
+
+	// Resolve paths of projects missing from the group listing
+	resolveMissingProjectPaths(init.ctx, init.app, issues, context)
+	if err := renderIssuesWithContext(issues, context, &opts); err != nil {
+		return err
+	}
+	if fetchErr != nil {
+		return partialResultError(init.ctx, len(issues))
+	}
+	return nil
 }
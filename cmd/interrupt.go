@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var errPartialResult = errors.New("incomplete report")
+
+// runContext returns the context of an issue command: the command context, cancelled
+// on Ctrl-C by Execute, bounded by deadline when it is positive.
+func runContext(cmd *cobra.Command, deadline time.Duration) (context.Context, context.CancelFunc) {
+	ctx := commandContext(cmd)
+	if deadline > 0 {
+		return context.WithTimeout(ctx, deadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+// commandContext returns the context of cmd, the background context when cmd was run
+// without one, as in tests.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// partialResultError describes a run stopped by Ctrl-C or --deadline after count
+// issues were fetched. It is returned once those issues have been rendered, so the
+// warning is the last thing printed and the exit status is non-zero.
+func partialResultError(ctx context.Context, count int) error {
+	reason := "interrupted"
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		reason = "--deadline reached"
+	}
+	return fmt.Errorf("%w (%s): only the %d issues fetched before stopping were reported",
+		errPartialResult, reason, count)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sgaunet/gitlab-issue-report/internal/core"
+	"github.com/sgaunet/gitlab-issue-report/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var errOfflineScope = errors.New("the issues command is not available with --offline, " +
+	"snapshots hold the issues of a project or group")
+
+// issuesCmd represents the issues command.
+var issuesCmd = &cobra.Command{
+	Use:   "issues",
+	Short: "Get your issues across the GitLab instance",
+	Long: `Retrieve and display issues from all the projects of the GitLab instance.
+
+--scope selects the issues: assigned_to_me (default), created_by_me, or all the
+issues you can see. No project or group is needed, the issues are listed like a
+group report, with a project column.
+
+EXAMPLES:
+  # Everything assigned to you
+  gitlab-issue-report issues
+
+  # Open issues you created, as markdown
+  gitlab-issue-report issues --scope created_by_me --state opened --format markdown
+
+  # Bugs updated in the last week across the instance
+  gitlab-issue-report issues --scope all --labels bug -U -i "/-7/ ::"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runIssuesReport(cmd)
+	},
+}
+
+// runIssuesReport reports on the issues of the whole instance in the scope selected
+// by the flags.
+func runIssuesReport(cmd *cobra.Command) error {
+	init, err := initIssueCommand(&opts, cmd)
+	if err != nil {
+		return err
+	}
+	defer init.cancel()
+	if opts.offline {
+		return errOfflineScope
+	}
+
+	options, err := buildIssueOptions(init.ctx, &opts, 0, 0, init.beginTime, init.endTime)
+	if err != nil {
+		return err
+	}
+	options = append(options, core.WithScope(opts.scope))
+
+	// Project paths are resolved from the issues, as for projects outside a group listing
+	context := render.NewInstanceContext(opts.scope, nil)
+	context.Query = buildQueryParams(&opts, init.beginTime, init.endTime)
+	if isStreamingFormat(opts.formatOutput) {
+		return streamIssuesWithContext(init.ctx, init.app, [][]core.GetIssuesOption{options}, context, &opts)
+	}
+
+	// Get and display issues. When interrupted, the issues fetched so far are reported.
+	issues, fetchErr := init.app.GetIssues(init.ctx, options...)
+	if fetchErr != nil && init.ctx.Err() == nil {
+		return fmt.Errorf("failed to get issues: %w", fetchErr)
+	}
+	resolveMissingProjectPaths(init.ctx, init.app, issues, context)
+	if err := renderIssuesWithContext(issues, context, &opts); err != nil {
+		return err
+	}
+	if fetchErr != nil {
+		return partialResultError(init.ctx, len(issues))
+	}
+	return nil
+}
+
+// scopeFlagUsage returns the help text of the --scope flag.
+func scopeFlagUsage() string {
+	return "Issues to list across the instance: " + strings.Join(core.Scopes, ", ")
+}
+
+func init() {
+	addReportFlags(issuesCmd)
+	issuesCmd.Flags().StringVar(&opts.scope, "scope", core.ScopeAssignedToMe, scopeFlagUsage())
+
+	rootCmd.AddCommand(issuesCmd)
+}
@@ -1,18 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
 	"os"
-	"path/filepath"
+	"path"
+	"slices"
 	"strings"
-	"time"
 
+	"github.com/sgaunet/gitlab-issue-report/internal/core"
 	"github.com/sgaunet/gitlab-issue-report/internal/render"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
-	"gopkg.in/ini.v1"
 )
 
 // projectCmd represents the project command.
@@ -20,6 +24,11 @@ var (
 	errGitRepositoryNotFound   = errors.New("git repository not found")
 	errGitlabTokenNotAvailable = errors.New("gitlab token not available")
 	errGitlabProjectNotFound   = errors.New("gitlab project not found")
+	errOfflineProjectID        = errors.New("--offline requires --project, the project cannot be detected offline")
+	errRemoteNotFound          = errors.New("git remote not found")
+	errNoMatchingRemote        = errors.New("no git remote on")
+	errAmbiguousRemote         = errors.New("git remotes point to different GitLab projects")
+	errRemoteHostMismatch      = errors.New("git remote is not on the GitLab host")
 )
 
 var projectCmd = &cobra.Command{
@@ -28,7 +37,8 @@ var projectCmd = &cobra.Command{
 	Long: `Retrieve and display issues from a GitLab project.
 
 The project ID can be auto-detected from your current git repository's
-remote URL, or specified explicitly with the -p flag.
+remote URL, or specified explicitly with the -p flag. Repeat -p to merge the
+issues of several projects into one report.
 
 EXAMPLES:
   # Auto-detect project from git remote
@@ -37,6 +47,9 @@ EXAMPLES:
   # Specify project ID
   gitlab-issue-report project -p 12345
 
+  # Several projects in one report, with a project column
+  gitlab-issue-report project -p 12 -p group/repo -p 99
+
   # Issues created in the last 2 weeks
   gitlab-issue-report project --created -i "/-14/ ::"
 
@@ -49,101 +62,230 @@ EXAMPLES:
   # Only issues assigned to you
   gitlab-issue-report project --mine`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		init, err := initIssueCommand(&opts, cmd)
-		if err != nil {
-			return err
-		}
+		return runProjectReport(cmd)
+	},
+}
+
+// runProjectReport reports on the issues of the project selected by the flags.
+func runProjectReport(cmd *cobra.Command) error {
+	init, err := initIssueCommand(&opts, cmd)
+	if err != nil {
+		return err
+	}
+	defer init.cancel()
+
+	// Find project ID if not specified.
+	projectIDs, err := resolveProjectIDs(init, &opts)
+	if err != nil {
+		return err
+	}
+	if len(projectIDs) > 1 {
+		return runProjectsReport(init, projectIDs)
+	}
+	finalProjectID := projectIDs[0]
+
+	// Build issue retrieval options.
+	options, err := buildIssueOptions(init.ctx, &opts, finalProjectID, 0, init.beginTime, init.endTime)
+	if err != nil {
+		return err
+	}
+
+	// Fetch project path for context. Without it, the report is rendered without context.
+	projectPath, pathErr := init.app.GetProjectPath(init.ctx, finalProjectID)
+	if pathErr != nil {
+		logrus.Warnf("Failed to fetch project path: %v", pathErr)
+	}
 
-		// Find project ID if not specified.
-		finalProjectID := opts.projectIDFlag
-		if finalProjectID == 0 {
-			finalProjectID, err = findProjectID(opts.apiTimeout)
-			if err != nil {
-				return err
-			}
+	// Streaming formats write each page as soon as it is fetched.
+	if isStreamingFormat(opts.formatOutput) {
+		if pathErr != nil {
+			projectPath = fmt.Sprintf("ID:%d", finalProjectID)
 		}
+		context := render.NewProjectContext(projectPath)
+		context.Query = buildQueryParams(&opts, init.beginTime, init.endTime)
+		return streamIssuesWithContext(init.ctx, init.app, [][]core.GetIssuesOption{options}, context, &opts)
+	}
 
-		// Build issue retrieval options.
-		options, err := buildIssueOptions(&opts, finalProjectID, 0, init.beginTime, init.endTime)
-		if err != nil {
-			return err
+	// Get and display issues. When interrupted, the issues fetched so far are reported.
+	issues, fetchErr := init.app.GetIssues(init.ctx, options...)
+	if fetchErr != nil && init.ctx.Err() == nil {
+		return fmt.Errorf("failed to get issues: %w", fetchErr)
+	}
+	if pathErr != nil {
+		err = renderIssues(issues, &opts)
+	} else {
+		context := render.NewProjectContext(projectPath)
+		context.Query = buildQueryParams(&opts, init.beginTime, init.endTime)
+		err = renderIssuesWithContext(issues, context, &opts)
+	}
+	if err != nil {
+		return err
+	}
+	if fetchErr != nil {
+		return partialResultError(init.ctx, len(issues))
+	}
+	return nil
+}
+
+// runProjectsReport reports on the issues of several projects in one report, with a
+// project column as for group reports. The projects are fetched one after the other.
+func runProjectsReport(init *commandInit, projectIDs []int64) error {
+	options, err := buildIssueOptions(init.ctx, &opts, 0, 0, init.beginTime, init.endTime)
+	if err != nil {
+		return err
+	}
+	queries := make([][]core.GetIssuesOption, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		queries = append(queries, append(slices.Clone(options), core.WithProjectID(id)))
+	}
+
+	context := newProjectsContext(init.ctx, init.app, projectIDs)
+	context.Query = buildQueryParams(&opts, init.beginTime, init.endTime)
+	if isStreamingFormat(opts.formatOutput) {
+		return streamIssuesWithContext(init.ctx, init.app, queries, context, &opts)
+	}
+
+	// When interrupted, the issues fetched so far are reported.
+	var issues []*gitlab.Issue
+	var fetchErr error
+	for _, query := range queries {
+		var projectIssues []*gitlab.Issue
+		projectIssues, fetchErr = init.app.GetIssues(init.ctx, query...)
+		issues = append(issues, projectIssues...)
+		if fetchErr != nil {
+			break
 		}
+	}
+	if fetchErr != nil && init.ctx.Err() == nil {
+		return fmt.Errorf("failed to get issues: %w", fetchErr)
+	}
+	if err := renderIssuesWithContext(issues, context, &opts); err != nil {
+		return err
+	}
+	if fetchErr != nil {
+		return partialResultError(init.ctx, len(issues))
+	}
+	return nil
+}
 
-		// Get and display issues.
-		issues, err := init.app.GetIssues(options...)
+// resolveProjectIDs returns the IDs of the projects given with --project, resolving
+// paths and web URLs and dropping duplicates, or the ID of the project of the git
+// repository of the current directory.
+func resolveProjectIDs(init *commandInit, o *commandOptions) ([]int64, error) {
+	if len(o.projectRefs) == 0 {
+		if o.offline {
+			return nil, errOfflineProjectID
+		}
+		id, err := findProjectID(init.ctx, o.remote, clientOptions(o))
 		if err != nil {
-			return fmt.Errorf("failed to get issues: %w", err)
+			return nil, err
 		}
-
-		// Fetch project path for context
-		projectPath, err := init.app.GetProjectPath(finalProjectID)
+		return []int64{id}, nil
+	}
+	ids := make([]int64, 0, len(o.projectRefs))
+	for _, ref := range o.projectRefs {
+		id, err := init.app.ResolveProject(init.ctx, ref)
 		if err != nil {
-			logrus.Warnf("Failed to fetch project path: %v", err)
-			// Fall back to rendering without context
-			return renderIssues(issues, opts.formatOutput)
+			return nil, fmt.Errorf("invalid project: %w", err)
 		}
-
-		// Create context and render
-		context := render.NewProjectContext(projectPath)
-		return renderIssuesWithContext(issues, context, opts.formatOutput)
-	},
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
 }
 
-// findProjectID attempts to determine the project ID if not specified.
-func findProjectID(timeout time.Duration) (int64, error) {
-	// Try to find git repository and project.
-	gitFolder, err := findGitRepository()
+// findProjectID determines the project of the git repository of the current directory
+// from the remote named remoteName, or from its remotes on GITLAB_URI when empty.
+func findProjectID(ctx context.Context, remoteName string, clientOpts core.ClientOptions) (int64, error) {
+	repo, err := findGitRepository()
 	if err != nil {
-		return 0, fmt.Errorf("git repository not found: %w", err)
+		return 0, err
 	}
-
-	// Get remote origin from git config.
-	configPath := filepath.Join(gitFolder, ".git", "config")
-	remoteOrigin, err := getRemoteOrigin(configPath)
+	remotes, err := getRemoteURLs(repo)
 	if err != nil {
 		return 0, err
 	}
 
-	project, err := findProject(remoteOrigin, timeout)
+	var found project
+	if remoteName != "" {
+		remoteURL, ok := remotes[remoteName]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q (remotes: %s)", errRemoteNotFound, remoteName, strings.Join(sortedKeys(remotes), ", "))
+		}
+		found, err = findProject(ctx, remoteURL, true, clientOpts)
+	} else {
+		found, err = detectProject(ctx, remotes, clientOpts)
+	}
 	if err != nil {
 		return 0, err
 	}
 
-	logrus.Infoln("Project found: ", project.SSHURLToRepo)
-	logrus.Infoln("Project found: ", project.ID)
-	return project.ID, nil
+	logrus.Infoln("Project found: ", found.SSHURLToRepo)
+	logrus.Infoln("Project found: ", found.ID)
+	return found.ID, nil
 }
 
-// findGitRepository locates the git repository in the current or parent directories.
-func findGitRepository() (string, error) {
-	cwd, err := os.Getwd()
+// detectProject returns the project of the remotes on the host of GITLAB_URI. Remotes
+// resolving to different projects are reported instead of picking one of them, as well
+// as the absence of any remote on this host.
+func detectProject(ctx context.Context, remotes map[string]string, clientOpts core.ClientOptions) (project, error) {
+	base, err := gitlabBaseURL()
 	if err != nil {
-		return "", fmt.Errorf("%w", errGitRepositoryNotFound)
+		return project{}, err
+	}
+	names := sortedKeys(remotes)
+	var candidates []string
+	listed := make([]string, 0, len(names)) // Remotes with their host, e.g. "origin (github.com)"
+	for _, name := range names {
+		remote, err := parseRemoteURL(remotes[name])
+		if err != nil {
+			logrus.Debugf("Ignoring remote %s: %v", name, err)
+			listed = append(listed, name)
+			continue
+		}
+		listed = append(listed, fmt.Sprintf("%s (%s)", name, remote.Host))
+		if remote.matchesHost(base.Hostname()) {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return project{}, fmt.Errorf("%w %s (remotes: %s), select one with --remote or set GITLAB_URI",
+			errNoMatchingRemote, base.Hostname(), strings.Join(listed, ", "))
 	}
 
-	for cwd != "/" {
-		logrus.Debugln(cwd)
-		stat, err := os.Stat(filepath.Join(cwd, ".git"))
-		if err == nil {
-			if stat.IsDir() {
-				return cwd, nil // Found git directory.
-			}
+	var projects []project
+	var resolved []string // Remotes of projects, e.g. "origin (group/project)"
+	for _, name := range candidates {
+		p, err := findProject(ctx, remotes[name], false, clientOpts)
+		if errors.Is(err, errGitlabProjectNotFound) {
+			logrus.Debugf("Remote %s: %v", name, err)
+			continue
+		}
+		if err != nil {
+			return project{}, fmt.Errorf("remote %s: %w", name, err)
 		}
-		cwd = filepath.Dir(cwd)
+		logrus.Debugf("Remote %s is project %d", name, p.ID)
+		if !slices.ContainsFunc(projects, func(other project) bool { return other.ID == p.ID }) {
+			projects = append(projects, p)
+		}
+		resolved = append(resolved, fmt.Sprintf("%s (project %d)", name, p.ID))
 	}
-	return "", fmt.Errorf("%w", errGitRepositoryNotFound)
-}
 
-// getRemoteOrigin retrieves the remote origin URL from the git configuration file.
-func getRemoteOrigin(gitConfigFile string) (string, error) {
-	cfg, err := ini.Load(gitConfigFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to read git config file: %w", err)
+	switch len(projects) {
+	case 0:
+		return project{}, fmt.Errorf("%w for remotes %s", errGitlabProjectNotFound, strings.Join(candidates, ", "))
+	case 1:
+		return projects[0], nil
+	default:
+		return project{}, fmt.Errorf("%w: %s, select one with --remote",
+			errAmbiguousRemote, strings.Join(resolved, ", "))
 	}
+}
 
-	url := cfg.Section("remote \"origin\"").Key("url").String()
-	logrus.Debugln("url:", url)
-	return url, nil
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys(m map[string]string) []string {
+	return slices.Sorted(maps.Keys(m))
 }
 
 type project struct {
@@ -153,43 +295,100 @@ type project struct {
 	HTTPURLToRepo string `json:"httpUrlToRepo"`
 }
 
-// findProject searches for a project in GitLab based on the remote origin URL.
-func findProject(remoteOrigin string, timeout time.Duration) (project, error) {
-	projectName := filepath.Base(remoteOrigin)
-	projectName = strings.ReplaceAll(projectName, ".git", "")
-	logrus.Infof("Try to find project %s in %s\n", projectName, os.Getenv("GITLAB_URI"))
+// findProject finds the GitLab project of the remote origin URL. The project is looked
+// up by its full path, the project search being only used when this fails, e.g. for
+// mirrors whose path differs from the one of the remote.
+//
+// The remote must be on the host of GITLAB_URI, directly or through an SSH host alias,
+// unless selected explicitly (--remote): it is then looked up on GITLAB_URI anyway,
+// e.g. for a mirror.
+func findProject(
+	ctx context.Context,
+	remoteOrigin string,
+	selected bool,
+	clientOpts core.ClientOptions,
+) (project, error) {
+	remote, err := parseRemoteURL(remoteOrigin)
+	if err != nil {
+		return project{}, err
+	}
 
 	gitlabToken := os.Getenv("GITLAB_TOKEN")
-	gitlabURI := os.Getenv("GITLAB_URI")
 	if gitlabToken == "" {
 		return project{}, fmt.Errorf("%w", errGitlabTokenNotAvailable)
 	}
-	if gitlabURI == "" {
-		gitlabURI = "https://gitlab.com"
-		logrus.Warnf("GITLAB_URI not set, defaulting to %s", gitlabURI)
+	base, err := gitlabBaseURL()
+	if err != nil {
+		return project{}, err
 	}
+	gitlabURI := base.String()
+	if !remote.matchesHost(base.Hostname()) {
+		if !selected {
+			return project{}, fmt.Errorf("%w: %s is on %s, not on %s (GITLAB_URI), select it with --remote "+
+				"to look it up anyway", errRemoteHostMismatch, remoteOrigin, remote.Host, base.Hostname())
+		}
+		logrus.Warnf("The git remote is on %s, not on %s (GITLAB_URI), looking the project up anyway",
+			remote.Host, base.Hostname())
+	}
+	projectPath := remote.projectPath(base)
+	logrus.Infof("Try to find project %s in %s\n", projectPath, gitlabURI)
 
-	git, err := createGitlabClient(gitlabToken, gitlabURI, timeout)
+	git, err := createGitlabClient(gitlabToken, gitlabURI, clientOpts)
 	if err != nil {
 		return project{}, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
 
-	searchOpts := &gitlab.SearchOptions{}
-	foundProjects, _, err := git.Search.Projects(projectName, searchOpts)
+	p, resp, err := git.Projects.GetProject(projectPath, nil, gitlab.WithContext(ctx))
+	if err == nil {
+		return newProject(p), nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return project{}, fmt.Errorf("failed to get project '%s': %w", projectPath, err)
+	}
+	logrus.Debugf("Project %s not found by path, searching it by name", projectPath)
+	return searchProject(ctx, git, remoteOrigin, projectPath)
+}
+
+// searchProject searches the projects named as the last element of projectPath for the
+// one having this path or the remote origin URL.
+func searchProject(ctx context.Context, git *gitlab.Client, remoteOrigin, projectPath string) (project, error) {
+	projectName := path.Base(projectPath)
+	searchOpts := &gitlab.SearchOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	foundProjects, _, err := git.Search.Projects(projectName, searchOpts, gitlab.WithContext(ctx))
 	if err != nil {
 		return project{}, fmt.Errorf("failed to search for project '%s': %w", projectName, err)
 	}
 
 	for _, p := range foundProjects {
 		logrus.Debugf("Found project: Name=%s, ID=%d, SSHURL=%s, HTTPURL=%s", p.Name, p.ID, p.SSHURLToRepo, p.HTTPURLToRepo)
-		if p.SSHURLToRepo == remoteOrigin {
-			return project{
-				ID:            p.ID,
-				Name:          p.Name,
-				SSHURLToRepo:  p.SSHURLToRepo,
-				HTTPURLToRepo: p.HTTPURLToRepo,
-			}, nil
+		if strings.EqualFold(p.PathWithNamespace, projectPath) ||
+			p.SSHURLToRepo == remoteOrigin || p.HTTPURLToRepo == remoteOrigin {
+			return newProject(p), nil
 		}
 	}
-	return project{}, fmt.Errorf("%w", errGitlabProjectNotFound)
+	return project{}, fmt.Errorf("%w: %s", errGitlabProjectNotFound, projectPath)
+}
+
+// gitlabBaseURL returns GITLAB_URI, https://gitlab.com when it is not set.
+func gitlabBaseURL() (*url.URL, error) {
+	gitlabURI := os.Getenv("GITLAB_URI")
+	if gitlabURI == "" {
+		gitlabURI = "https://gitlab.com"
+		logrus.Warnf("GITLAB_URI not set, defaulting to %s", gitlabURI)
+	}
+	base, err := url.Parse(gitlabURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GITLAB_URI %q: %w", gitlabURI, err)
+	}
+	return base, nil
+}
+
+// newProject returns the fields of a GitLab project used to report it.
+func newProject(p *gitlab.Project) project {
+	return project{
+		ID:            p.ID,
+		Name:          p.Name,
+		SSHURLToRepo:  p.SSHURLToRepo,
+		HTTPURLToRepo: p.HTTPURLToRepo,
+	}
 }
@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sgaunet/gitlab-issue-report/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	errQueryUnknownFlag = errors.New("unknown flag in saved query")
+	errQueryEmpty       = errors.New("no flag given, nothing to save")
+	errQueryExists      = errors.New("query already exists, use --force to replace it")
+	errQuerySourceFlags = errors.New("only one of --project, --group and --scope can be used")
+)
+
+// Flags never stored in a saved query.
+var unsavedQueryFlags = []string{"config", "force"}
+
+// Flags taking a project or group ID, path or web URL.
+var referenceFlags = []string{"project", "group"}
+
+// Flags selecting the source of the report: one of them given on the command line
+// replaces the source of the query.
+var sourceFlags = []string{"project", "group", "scope"}
+
+// queryForce holds the value of the --force flag of query save.
+var queryForce bool
+
+// runCmd represents the run command.
+var runCmd = &cobra.Command{
+	Use:   "run <query>",
+	Short: "Run a saved query",
+	Long: `Run a query saved in the config file with query save.
+
+The query gives the project, group or scope and the flags of the report. Flags
+given on the command line take precedence over the values of the query, and
+--project, --group or --scope replace the source of the query.
+
+EXAMPLES:
+  # Run the weekly-bugs query
+  gitlab-issue-report run weekly-bugs
+
+  # Same report over the last month, as HTML
+  gitlab-issue-report run weekly-bugs -i "/-1/ ::" --format html`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(&opts)
+		if err != nil {
+			return err
+		}
+		if cfg == nil {
+			return fmt.Errorf("%w, no saved query", errConfigNotFound)
+		}
+		query, err := cfg.Query(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to run query: %w", err)
+		}
+		if err := applyQuery(cmd, query); err != nil {
+			return fmt.Errorf("query %q: %w", args[0], err)
+		}
+		if sourceCount(&opts) > 1 {
+			return errQuerySourceFlags
+		}
+		if opts.scope != "" {
+			return runIssuesReport(cmd)
+		}
+		if opts.groupRef != "" {
+			return runGroupReport(cmd)
+		}
+		return runProjectReport(cmd)
+	},
+}
+
+// queryCmd represents the query command.
+var queryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Manage the saved queries",
+	Long: `Manage the queries saved in the config file, run with gitlab-issue-report run.
+
+EXAMPLES:
+  # Save the unassigned bugs of a group updated in the last week
+  gitlab-issue-report query save weekly-bugs -g 678 --labels bug --assignee none -U -i "/-7/ ::"
+
+  # List, show and delete the saved queries
+  gitlab-issue-report query list
+  gitlab-issue-report query show weekly-bugs
+  gitlab-issue-report query delete weekly-bugs`,
+}
+
+// queryListCmd represents the query list command.
+var queryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the saved queries",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg, err := loadConfig(&opts)
+		if err != nil {
+			return err
+		}
+		if cfg == nil {
+			cfg = &config.Config{}
+		}
+		return writeQueryList(cmd.OutOrStdout(), cfg)
+	},
+}
+
+// queryShowCmd represents the query show command.
+var queryShowCmd = &cobra.Command{
+	Use:   "show <query>",
+	Short: "Show a saved query",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(&opts)
+		if err != nil {
+			return err
+		}
+		if cfg == nil {
+			return errConfigNotFound
+		}
+		query, err := cfg.Query(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to show query: %w", err)
+		}
+		out, err := yaml.Marshal(query)
+		if err != nil {
+			return fmt.Errorf("failed to encode query: %w", err)
+		}
+		text := fmt.Sprintf("# gitlab-issue-report run %s\n# %s\n%s", args[0], queryCommandLine(query), out)
+		if _, err := io.WriteString(cmd.OutOrStdout(), text); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	},
+}
+
+// querySaveCmd represents the query save command.
+var querySaveCmd = &cobra.Command{
+	Use:   "save <query> [flags]",
+	Short: "Save the given flags as a named query",
+	Long: `Save the flags given on the command line as a named query of the config file.
+
+--project, --group or --scope select the source of the report, the project of the
+current git repository being used when none is given. The other flags are those of
+the project, group and issues commands.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if sourceCount(&opts) > 1 {
+			return errQuerySourceFlags
+		}
+		if err := validateFlags(&opts); err != nil {
+			return err
+		}
+		query := queryFromFlags(cmd)
+		if len(query) == 0 {
+			return errQueryEmpty
+		}
+
+		path, err := configPath(&opts)
+		if err != nil {
+			return err
+		}
+		if cfg, err := loadConfig(&opts); err == nil && cfg != nil && !queryForce {
+			if _, exists := cfg.Queries[args[0]]; exists {
+				return fmt.Errorf("%w: %q", errQueryExists, args[0])
+			}
+		}
+		if err := config.SaveQuery(path, args[0], query); err != nil {
+			return fmt.Errorf("failed to save query: %w", err)
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Saved query %s in %s\n", args[0], path); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	},
+}
+
+// queryDeleteCmd represents the query delete command.
+var queryDeleteCmd = &cobra.Command{
+	Use:   "delete <query>",
+	Short: "Delete a saved query",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath(&opts)
+		if err != nil {
+			return err
+		}
+		if err := config.DeleteQuery(path, args[0]); err != nil {
+			return fmt.Errorf("failed to delete query: %w", err)
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Deleted query %s\n", args[0]); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	},
+}
+
+// applyQuery sets the flags of the query not given on the command line. A project,
+// group or scope given on the command line replaces the source of the query.
+func applyQuery(cmd *cobra.Command, query config.Query) error {
+	sourceChanged := slices.ContainsFunc(sourceFlags, cmd.Flags().Changed)
+	for _, name := range query.Names() {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("%w: --%s", errQueryUnknownFlag, name)
+		}
+		if flag.Changed || (sourceChanged && slices.Contains(sourceFlags, name)) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, query.Value(name)); err != nil {
+			return fmt.Errorf("invalid --%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// sourceCount returns the number of sources of the report given: projects, group and
+// scope.
+func sourceCount(o *commandOptions) int {
+	count := 0
+	for _, set := range []bool{len(o.projectRefs) > 0, o.groupRef != "", o.scope != ""} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// queryFromFlags returns the query made of the flags given on the command line.
+func queryFromFlags(cmd *cobra.Command) config.Query {
+	query := make(config.Query)
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if slices.Contains(unsavedQueryFlags, flag.Name) {
+			return
+		}
+		switch value := flag.Value.(type) {
+		case pflag.SliceValue:
+			values := value.GetSlice()
+			if len(values) == 1 && slices.Contains(referenceFlags, flag.Name) {
+				query[flag.Name] = referenceValue(values[0])
+			} else {
+				query[flag.Name] = values
+			}
+		default:
+			query[flag.Name] = typedFlagValue(flag)
+		}
+	})
+	return query
+}
+
+// typedFlagValue returns the value of a scalar flag as a boolean or an integer when
+// the flag has this type, or is a project or group given by ID, so that the config
+// file reads naturally.
+func typedFlagValue(flag *pflag.Flag) any {
+	text := flag.Value.String()
+	switch {
+	case flag.Value.Type() == "bool":
+		if value, err := strconv.ParseBool(text); err == nil {
+			return value
+		}
+	case flag.Value.Type() == "int", flag.Value.Type() == "int64":
+		if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return value
+		}
+	case slices.Contains(referenceFlags, flag.Name):
+		return referenceValue(text)
+	}
+	return text
+}
+
+// referenceValue returns a project or group given by ID as an integer, by path or URL
+// as is.
+func referenceValue(ref string) any {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return id
+	}
+	return ref
+}
+
+// writeQueryList writes one line per saved query with the equivalent flags.
+func writeQueryList(w io.Writer, cfg *config.Config) error {
+	var b strings.Builder
+	if len(cfg.Queries) == 0 {
+		b.WriteString("No saved query, see gitlab-issue-report query save --help\n")
+	}
+	for _, name := range cfg.QueryNames() {
+		b.WriteString(name + "\t" + queryCommandLine(cfg.Queries[name]) + "\n")
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := io.WriteString(tw, b.String()); err != nil {
+		return fmt.Errorf("failed to write query list: %w", err)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write query list: %w", err)
+	}
+	return nil
+}
+
+// queryCommandLine returns the flags of the query as they would be typed, values
+// with spaces or shell characters being quoted.
+func queryCommandLine(query config.Query) string {
+	args := make([]string, 0, len(query))
+	for _, name := range query.Names() {
+		value := query.Value(name)
+		switch {
+		case value == "true":
+			args = append(args, "--"+name)
+		case value == "false":
+			args = append(args, "--"+name+"=false")
+		case strings.ContainsAny(value, " \t'\"$*?:;&|<>()\\"):
+			args = append(args, "--"+name+" '"+strings.ReplaceAll(value, "'", `'\''`)+"'")
+		default:
+			args = append(args, "--"+name+" "+value)
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// configPath returns the path of the config file given with --config, or the default one.
+func configPath(o *commandOptions) (string, error) {
+	if o.configFile != "" {
+		return o.configFile, nil
+	}
+	path, err := config.DefaultPath()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errConfigNotFound, err)
+	}
+	return path, nil
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{runCmd, querySaveCmd} {
+		addReportFlags(cmd)
+		cmd.Flags().StringSliceVarP(&opts.projectRefs, "project", "p", nil,
+			"Project ID, path or web URL to get issues from, repeat for several projects")
+		cmd.Flags().StringVarP(&opts.groupRef, "group", "g", "", "Group ID, path or web URL to get issues from")
+		cmd.Flags().StringVar(&opts.scope, "scope", "", scopeFlagUsage())
+		cmd.Flags().StringVar(&opts.remote, "remote", "", remoteFlagUsage)
+	}
+	querySaveCmd.Flags().BoolVar(&queryForce, "force", false, "Replace the query if it already exists")
+
+	queryCmd.AddCommand(queryListCmd, queryShowCmd, querySaveCmd, queryDeleteCmd)
+	rootCmd.AddCommand(runCmd, queryCmd)
+}
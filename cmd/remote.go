@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	errUnsupportedRemote = errors.New("unsupported git remote URL")
+	errRemoteNoNamespace = errors.New("git remote URL has no project namespace")
+)
+
+// scpRemote matches the scp-like syntax of SSH remotes, [user@]host:path, where the
+// host contains no slash so that local paths are not mistaken for remotes.
+var scpRemote = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// gitRemote is a git remote URL split into the host and the full path of the project.
+type gitRemote struct {
+	Host string // Host name, without user or port
+	Path string // Full path of the project, e.g. "group/subgroup/project"
+	SSH  bool   // Whether the remote uses SSH, whose host may be an alias of ~/.ssh/config
+}
+
+// parseRemoteURL parses the git remote URLs GitLab hands out or accepts:
+//
+//	https://gitlab.example.com/group/subgroup/project.git
+//	ssh://git@gitlab.example.com:2222/group/subgroup/project.git
+//	git@gitlab.example.com:group/subgroup/project.git
+//	gitlab-work:group/project (SSH host alias)
+func parseRemoteURL(remote string) (gitRemote, error) {
+	remote = strings.TrimSpace(remote)
+	var r gitRemote
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return gitRemote{}, fmt.Errorf("%w: %q: %w", errUnsupportedRemote, remote, err)
+		}
+		switch u.Scheme {
+		case "https", "http", "git":
+		case "ssh", "git+ssh", "ssh+git":
+			r.SSH = true
+		default:
+			return gitRemote{}, fmt.Errorf("%w: %q", errUnsupportedRemote, remote)
+		}
+		r.Host, r.Path = u.Hostname(), u.Path
+	} else {
+		match := scpRemote.FindStringSubmatch(remote)
+		if match == nil {
+			return gitRemote{}, fmt.Errorf("%w: %q", errUnsupportedRemote, remote)
+		}
+		r.Host, r.Path, r.SSH = match[1], match[2], true
+	}
+	if r.Host == "" {
+		return gitRemote{}, fmt.Errorf("%w: %q", errUnsupportedRemote, remote)
+	}
+
+	r.Path = strings.TrimSuffix(strings.Trim(r.Path, "/"), ".git")
+	if !strings.Contains(r.Path, "/") {
+		return gitRemote{}, fmt.Errorf("%w: %q", errRemoteNoNamespace, remote)
+	}
+	return r, nil
+}
+
+// projectPath returns the path of the project on the GitLab instance at base. GitLab
+// installed under a relative URL, e.g. https://example.com/gitlab, prefixes the paths
+// of its HTTP remotes with it.
+func (r gitRemote) projectPath(base *url.URL) string {
+	prefix := strings.Trim(base.Path, "/")
+	if prefix != "" && !r.SSH {
+		if rest, ok := strings.CutPrefix(r.Path, prefix+"/"); ok {
+			return rest
+		}
+	}
+	return r.Path
+}
+
+// matchesHost reports whether the remote is on host, resolving the SSH host aliases of
+// ~/.ssh/config.
+func (r gitRemote) matchesHost(host string) bool {
+	if strings.EqualFold(r.Host, host) {
+		return true
+	}
+	if !r.SSH {
+		return false
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	hostName := sshHostName(filepath.Join(home, ".ssh", "config"), r.Host)
+	return strings.EqualFold(hostName, host)
+}
+
+// sshHostName returns the HostName given to alias by the SSH config file at configFile,
+// empty when there is none. Only Host blocks are considered, as ssh does, the first
+// matching HostName wins; Match blocks and Include directives are ignored.
+func sshHostName(configFile, alias string) string {
+	file, err := os.Open(configFile)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+
+	matching := true // Settings before the first Host block apply to every host
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value := line, ""
+		if i := strings.IndexAny(line, " \t="); i >= 0 {
+			key, value = line[:i], strings.TrimLeft(line[i:], " \t=")
+		}
+		fields := strings.Fields(value)
+		switch strings.ToLower(key) {
+		case "host":
+			matching = sshHostMatches(fields, alias)
+		case "match":
+			matching = false
+		case "hostname":
+			if matching && len(fields) > 0 {
+				return strings.ReplaceAll(fields[0], "%h", alias)
+			}
+		}
+	}
+	return ""
+}
+
+// sshHostMatches reports whether alias matches the patterns of a Host line, where a
+// pattern starting with ! excludes the hosts it matches.
+func sshHostMatches(patterns []string, alias string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if ok, _ := path.Match(strings.ToLower(strings.TrimPrefix(pattern, "!")), strings.ToLower(alias)); ok {
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
@@ -2,12 +2,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"time"
 
+	"github.com/sgaunet/gitlab-issue-report/internal/config"
+	"github.com/sgaunet/gitlab-issue-report/internal/core"
 	"github.com/spf13/cobra"
 )
 
+// remoteFlagUsage is the help of the --remote flag of the commands detecting the project.
+const remoteFlagUsage = "Git remote used to detect the project (default: the remotes on the host of GITLAB_URI)"
+
 const (
 	defaultAPITimeout = 30 * time.Second // Default timeout for GitLab API requests
 )
@@ -15,19 +23,54 @@ const (
 // commandOptions holds all CLI flag values for issue commands.
 type commandOptions struct {
 	logLevel      string        // Log level: info, warn, error, debug
-	projectIDFlag int64         // Project ID
-	groupIDFlag   int64         // Group ID
+	projectRefs   []string      // Project IDs, paths or web URLs
+	groupRef      string        // Group ID, path or web URL
+	remote        string        // Git remote of the project, the remotes on GITLAB_URI when empty
+	scope         string        // Issues of the whole instance: assigned_to_me, created_by_me or all
 	createdFilter bool          // Filter by created date
 	updatedFilter bool          // Filter by updated date
 	stateFilter   string        // Filter by state: "opened", "closed", "all"
-	formatOutput  string        // Output format: "plain", "table", "markdown"
+	formatOutput  string        // Output format, one of validFormats
 	debugFlag     bool          // Shorthand for debug logging
 	verboseFlag   bool          // Shorthand for verbose logging
 	interval      string        // Date interval
 	mineOption    bool          // Filter issues assigned to current user
 	labelsFilter  []string      // Filter issues by labels (AND semantics)
+	notLabels     []string      // Exclude issues having any of these labels
+	anyLabels     []string      // Filter issues by labels (OR semantics)
+	milestone     string        // Filter by milestone title, or none/any/started/upcoming
+	author        string        // Filter by author username
+	assignee      string        // Filter by assignee username, or none
+	search        string        // Filter by text in title or description
 	apiTimeout    time.Duration // API request timeout
+	concurrency   int           // Number of issue pages fetched in parallel
+	maxRetries    int           // Retries of API requests answered with 429 or 5xx
+	deadline      time.Duration // Maximum duration of the whole run, 0 for none
+	retryWait     time.Duration // Initial backoff between retries
 	timezone      string        // Timezone for date calculations
+	columns       []string      // Columns to display, in order
+	sortKeys      []string      // Sort keys, e.g. "updated:desc"
+	groupBy       string        // Field to split the report into sections
+	templateFile  string        // Path of a Go text/template (--format template)
+	templateText  string        // Inline Go text/template (--format template)
+	summary       bool          // Append the summary statistics block
+	summaryOnly   bool          // Print only the summary statistics block
+	cacheDir      string        // Cache directory, the user cache directory when empty
+	noCache       bool          // Always fetch every issue from GitLab
+	refresh       bool          // Discard the cached issues and download them again
+	offline       bool          // Answer from the saved snapshots, without network calls
+	snapshotDir   string        // Snapshot directory, the user data directory when empty
+	configFile    string        // Config file, the user config directory when empty
+	profile       string        // Profile of the config file, default_profile when empty
+
+	tokenProfile   *config.Profile // Token settings of the applied profile, set by applyProfile
+	ignoreEnvToken bool            // The profile replaced GITLAB_URI by its instance, set by applyProfile
+
+	caCert             string // PEM file of additional trusted CAs
+	clientCert         string // PEM client certificate for mutual TLS
+	clientKey          string // PEM private key of the client certificate
+	proxy              string // Proxy URL, the proxy environment variables when empty
+	insecureSkipVerify bool   // Accept any server certificate
 }
 
 // opts is the package-level command options instance for Cobra flag binding.
@@ -44,11 +87,29 @@ Supports multiple output formats and can auto-detect your project from
 the current git repository.
 
 AUTHENTICATION:
-  Set the GITLAB_TOKEN environment variable with your GitLab personal access token.
+  Set the GITLAB_TOKEN environment variable with your GitLab personal access token,
+  or GITLAB_TOKEN_FILE with the path of a file holding it (mode 600). Otherwise the
+  token, token_file or token_command of the config profile is used, then the token
+  saved by the glab CLI for the GitLab host.
   Optionally set GITLAB_URI for self-hosted instances (defaults to https://gitlab.com).
   Optionally set GITLAB_API_TIMEOUT for custom API timeout (e.g., "1m").
   Optionally set GITLAB_TIMEZONE for date calculation timezone (e.g., "America/New_York").
 
+CONFIGURATION:
+  Profiles of the config file (--config, by default gitlab-issue-report/config.yaml
+  under the user config directory) hold the URI, token or token command, timeout,
+  timezone, TLS and proxy settings, default format and default group of each GitLab
+  instance. Select one with --profile or GITLAB_PROFILE. Flags take precedence over
+  environment variables, which take precedence over the profile, then over built-in
+  defaults. The token, TLS settings and group of a profile are only used on the
+  instance of its URI: a profile selected with --profile for another instance than
+  GITLAB_URI uses its own URI and token, never GITLAB_TOKEN.
+
+TLS AND PROXY:
+  --ca-cert adds the CA of a self-hosted instance, --client-cert and --client-key
+  present a client certificate, and --proxy overrides the HTTPS_PROXY environment
+  variable. --insecure-skip-verify disables certificate checks, for testing only.
+
 EXAMPLES:
   # Auto-detect project from current git repository
   gitlab-issue-report project
@@ -56,9 +117,16 @@ EXAMPLES:
   # Specify a project ID explicitly
   gitlab-issue-report project -p 12345
 
+  # Specify a project or group by path, or by a URL copied from the browser
+  gitlab-issue-report project -p mygroup/backend/api
+  gitlab-issue-report group -g https://gitlab.com/mygroup/backend
+
   # Get issues from a group
   gitlab-issue-report group -g 678
 
+  # Everything assigned to you across the instance
+  gitlab-issue-report issues
+
   # Filter by date interval (last 7 days)
   gitlab-issue-report project -i "/-7/ ::"
 
@@ -68,6 +136,15 @@ EXAMPLES:
   # Output as a markdown table
   gitlab-issue-report project --format markdown
 
+  # Output as JSON for scripts and dashboards
+  gitlab-issue-report group -g 678 --format json
+
+  # Stream one JSON object per line as pages arrive
+  gitlab-issue-report group -g 678 --format ndjson | jq -r .title
+
+  # Export to a spreadsheet
+  gitlab-issue-report group -g 678 --format csv > issues.csv
+
   # Combine filters: closed issues from last 30 days
   gitlab-issue-report project -p 12345 -i "/-30/ ::" --state closed --format table
 
@@ -77,6 +154,34 @@ EXAMPLES:
   # Filter by labels (issues must have ALL listed labels)
   gitlab-issue-report project --labels bug,backend
 
+  # Unassigned bugs of the current milestone, excluding duplicates
+  gitlab-issue-report group -g 678 --milestone started --assignee none --labels bug --not-labels duplicate
+
+  # Issues labelled frontend OR backend, opened by alice
+  gitlab-issue-report project --any-label frontend,backend --author alice
+
+  # Choose the columns to display
+  gitlab-issue-report project --columns iid,title,assignees,milestone,due
+
+  # Sort by due date, then most recently updated first
+  gitlab-issue-report project --sort due,updated:desc
+
+  # One section per assignee
+  gitlab-issue-report group -g 678 --group-by assignee
+
+  # How many issues were opened vs closed this month
+  gitlab-issue-report group -g 678 -i "/-1/ ::" --summary-only
+
+  # Self-contained HTML page to share with stakeholders
+  gitlab-issue-report group -g 678 --format html > report.html
+
+  # Custom report layout from a Go template
+  gitlab-issue-report project --format template --template weekly.tmpl
+
+  # Save the issues of a group, then report on them without network access
+  gitlab-issue-report snapshot save -g 678
+  gitlab-issue-report group -g 678 --offline --state opened --labels bug
+
   # Use a specific timezone for date calculations
   gitlab-issue-report project -i "/-7/ ::" --timezone "America/New_York"
 
@@ -86,8 +191,13 @@ For more details on each subcommand:
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// The first Ctrl-C cancels the command context, a second one terminates the process.
 func Execute() error {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		return fmt.Errorf("command execution failed: %w", err)
 	}
 	return nil
@@ -97,62 +207,109 @@ func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	// ===== PERSISTENT FLAGS (ALL COMMANDS) =====
+	rootCmd.PersistentFlags().StringVar(&opts.configFile, "config", "",
+		"Config file (default: gitlab-issue-report/config.yaml under the user config directory)")
+	rootCmd.PersistentFlags().StringVar(&opts.profile, "profile", "",
+		"Profile of the config file to use (default: $GITLAB_PROFILE, then default_profile)")
 	rootCmd.PersistentFlags().DurationVar(&opts.apiTimeout, "api-timeout", defaultAPITimeout,
 		"Timeout for GitLab API requests (e.g., 30s, 1m)")
 	rootCmd.PersistentFlags().StringVarP(&opts.timezone, "timezone", "T", "",
 		"Timezone for date calculations (e.g., America/New_York, UTC, Local)")
+	rootCmd.PersistentFlags().IntVar(&opts.concurrency, "concurrency", core.DefaultConcurrency,
+		"Number of issue pages fetched in parallel (1 fetches pages one at a time)")
+	rootCmd.PersistentFlags().IntVar(&opts.maxRetries, "max-retries", core.DefaultMaxRetries,
+		"Retries of API requests rate limited (429) or failing with a server error (5xx)")
+	rootCmd.PersistentFlags().DurationVar(&opts.retryWait, "retry-wait", core.DefaultRetryWait,
+		"Initial wait between retries, doubled after each retry unless GitLab says how long to wait")
+	rootCmd.PersistentFlags().DurationVar(&opts.deadline, "deadline", 0,
+		"Maximum duration of the whole run (e.g., 2m); issues fetched so far are reported when reached")
+	rootCmd.PersistentFlags().StringVar(&opts.cacheDir, "cache-dir", "",
+		"Directory of the issue cache (default: gitlab-issue-report under the user cache directory)")
+	rootCmd.PersistentFlags().BoolVar(&opts.noCache, "no-cache", false,
+		"Fetch every issue from GitLab without reading or updating the cache")
+	rootCmd.PersistentFlags().BoolVar(&opts.refresh, "refresh", false,
+		"Discard the cached issues of the project or group and download them again")
+	rootCmd.PersistentFlags().BoolVar(&opts.offline, "offline", false,
+		"Build reports from the snapshots saved with snapshot save, without any network call")
+	rootCmd.PersistentFlags().StringVar(&opts.snapshotDir, "snapshot-dir", "",
+		"Directory of the snapshots (default: gitlab-issue-report/snapshots under the user data directory)")
+
+	rootCmd.PersistentFlags().StringVar(&opts.caCert, "ca-cert", "",
+		"PEM file of the CA certificates of the GitLab server, trusted next to the system ones")
+	rootCmd.PersistentFlags().StringVar(&opts.clientCert, "client-cert", "",
+		"PEM file of the client certificate for mutual TLS (requires --client-key)")
+	rootCmd.PersistentFlags().StringVar(&opts.clientKey, "client-key", "",
+		"PEM file of the private key of --client-cert")
+	rootCmd.PersistentFlags().StringVar(&opts.proxy, "proxy", "",
+		"Proxy URL, e.g. http://proxy:3128 (default: $HTTPS_PROXY, $HTTP_PROXY and $NO_PROXY)")
+	rootCmd.PersistentFlags().BoolVar(&opts.insecureSkipVerify, "insecure-skip-verify", false,
+		"Do not verify the certificate of the GitLab server (insecure, for testing only)")
 
 	// ===== PROJECT COMMAND FLAGS =====
 
 	// Project command flags
-	projectCmd.Flags().StringVarP(&opts.interval, "interval", "i", "", "Date interval (e.g., '/-1/ ::' for last month)")
-	projectCmd.Flags().StringVar(&opts.logLevel, "log-level", "error", "Log level: info, warn, error, debug")
-	projectCmd.Flags().BoolVarP(&opts.debugFlag, "debug", "d", false,
-		"Enable debug logging (shorthand for --log-level=debug)")
-	projectCmd.Flags().BoolVarP(&opts.verboseFlag, "verbose", "v", false,
-		"Enable verbose logging (shorthand for --log-level=info)")
-
-	projectCmd.Flags().Int64Var(&opts.projectIDFlag, "project-id", 0,
-		"Project ID to get issues from (auto-detected from git if not set)")
-	projectCmd.Flags().Int64VarP(&opts.projectIDFlag, "project", "p", 0, "Project ID (alias for --project-id)")
-
-	projectCmd.Flags().BoolVar(&opts.createdFilter, "created", false,
-		"Filter issues by creation date (requires --interval)")
-	projectCmd.Flags().BoolVarP(&opts.updatedFilter, "updated", "U", false,
-		"Filter issues by update date (requires --interval)")
-
-	projectCmd.Flags().StringVar(&opts.stateFilter, "state", "", "Filter by state: opened, closed, all")
-	projectCmd.Flags().StringVar(&opts.formatOutput, "format", "plain", "Output format: plain, table, markdown")
-
-	projectCmd.Flags().BoolVarP(&opts.mineOption, "mine", "M", false, "Only issues assigned to current user")
-	projectCmd.Flags().StringSliceVarP(&opts.labelsFilter, "labels", "l", nil,
-		"Filter by labels (comma-separated or repeated; issue must have ALL listed labels)")
+	addReportFlags(projectCmd)
+	projectCmd.Flags().StringSliceVar(&opts.projectRefs, "project-id", nil,
+		"Project ID, path (group/project) or web URL to get issues from, repeat for several projects "+
+			"(auto-detected from git if not set)")
+	projectCmd.Flags().StringSliceVarP(&opts.projectRefs, "project", "p", nil,
+		"Project ID, path or web URL (alias for --project-id)")
+	projectCmd.Flags().StringVar(&opts.remote, "remote", "", remoteFlagUsage)
 
 	rootCmd.AddCommand(projectCmd)
 
 	// ===== GROUP COMMAND FLAGS =====
 
 	// Group command flags
-	groupCmd.Flags().StringVarP(&opts.interval, "interval", "i", "", "Date interval (e.g., '/-1/ ::' for last month)")
-	groupCmd.Flags().StringVar(&opts.logLevel, "log-level", "error", "Log level: info, warn, error, debug")
-	groupCmd.Flags().BoolVarP(&opts.debugFlag, "debug", "d", false,
+	addReportFlags(groupCmd)
+	groupCmd.Flags().StringVar(&opts.groupRef, "group-id", "",
+		"Group ID, path (group/subgroup) or web URL to get issues from (required)")
+	groupCmd.Flags().StringVarP(&opts.groupRef, "group", "g", "", "Group ID, path or web URL (alias for --group-id)")
+
+	rootCmd.AddCommand(groupCmd)
+}
+
+// addReportFlags registers the logging, filter and output flags of the commands
+// producing a report.
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&opts.interval, "interval", "i", "", "Date interval (e.g., '/-1/ ::' for last month)")
+	cmd.Flags().StringVar(&opts.logLevel, "log-level", "error", "Log level: info, warn, error, debug")
+	cmd.Flags().BoolVarP(&opts.debugFlag, "debug", "d", false,
 		"Enable debug logging (shorthand for --log-level=debug)")
-	groupCmd.Flags().BoolVarP(&opts.verboseFlag, "verbose", "v", false,
+	cmd.Flags().BoolVarP(&opts.verboseFlag, "verbose", "v", false,
 		"Enable verbose logging (shorthand for --log-level=info)")
 
-	groupCmd.Flags().Int64Var(&opts.groupIDFlag, "group-id", 0, "Group ID to get issues from (required)")
-	groupCmd.Flags().Int64VarP(&opts.groupIDFlag, "group", "g", 0, "Group ID (alias for --group-id)")
-
-	groupCmd.Flags().BoolVar(&opts.createdFilter, "created", false, "Filter issues by creation date (requires --interval)")
-	groupCmd.Flags().BoolVarP(&opts.updatedFilter, "updated", "U", false,
+	cmd.Flags().BoolVar(&opts.createdFilter, "created", false,
+		"Filter issues by creation date (requires --interval)")
+	cmd.Flags().BoolVarP(&opts.updatedFilter, "updated", "U", false,
 		"Filter issues by update date (requires --interval)")
 
-	groupCmd.Flags().StringVar(&opts.stateFilter, "state", "", "Filter by state: opened, closed, all")
-	groupCmd.Flags().StringVar(&opts.formatOutput, "format", "plain", "Output format: plain, table, markdown")
+	cmd.Flags().StringVar(&opts.stateFilter, "state", "", "Filter by state: opened, closed, all")
+	cmd.Flags().StringVar(&opts.formatOutput, "format", "plain", formatFlagUsage())
 
-	groupCmd.Flags().BoolVarP(&opts.mineOption, "mine", "M", false, "Only issues assigned to current user")
-	groupCmd.Flags().StringSliceVarP(&opts.labelsFilter, "labels", "l", nil,
+	cmd.Flags().BoolVarP(&opts.mineOption, "mine", "M", false, "Only issues assigned to current user")
+	cmd.Flags().StringSliceVarP(&opts.labelsFilter, "labels", "l", nil,
 		"Filter by labels (comma-separated or repeated; issue must have ALL listed labels)")
-
-	rootCmd.AddCommand(groupCmd)
+	cmd.Flags().StringSliceVar(&opts.notLabels, "not-labels", nil,
+		"Exclude issues having ANY of these labels (comma-separated or repeated)")
+	cmd.Flags().StringSliceVar(&opts.anyLabels, "any-label", nil,
+		"Filter by labels (comma-separated or repeated; issue must have AT LEAST ONE listed label)")
+	cmd.Flags().StringVar(&opts.milestone, "milestone", "",
+		"Filter by milestone title, or none, any, started, upcoming")
+	cmd.Flags().StringVar(&opts.author, "author", "", "Filter by author username")
+	cmd.Flags().StringVar(&opts.assignee, "assignee", "",
+		"Filter by assignee username, or none for unassigned issues")
+	cmd.Flags().StringVar(&opts.search, "search", "", "Filter by text in title or description")
+
+	cmd.Flags().StringSliceVar(&opts.columns, "columns", nil, columnsFlagUsage())
+	cmd.Flags().StringSliceVar(&opts.sortKeys, "sort", nil, sortFlagUsage())
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", "",
+		"Split the report into sections: project, assignee, milestone, state, or a scoped label prefix (e.g. priority::)")
+	cmd.Flags().StringVar(&opts.templateFile, "template", "",
+		"Path of a Go text/template file (requires --format template)")
+	cmd.Flags().StringVar(&opts.templateText, "template-string", "",
+		"Inline Go text/template (requires --format template)")
+	cmd.Flags().BoolVar(&opts.summary, "summary", false,
+		"Append summary statistics: totals by state, label, assignee and project, overdue count, median open age")
+	cmd.Flags().BoolVar(&opts.summaryOnly, "summary-only", false, "Print only the summary statistics")
 }
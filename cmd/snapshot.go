@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sgaunet/gitlab-issue-report/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errSnapshotOffline     = errors.New("snapshot save cannot be used with --offline")
+	errSnapshotConflictIDs = errors.New("--project and --group cannot be used together")
+)
+
+// snapshotCmd represents the snapshot command.
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save issues locally to build reports offline",
+	Long: `Save issues locally to build reports offline.
+
+A snapshot holds every issue of a project or group, whatever its state, with
+the project and group paths. The project and group commands run with --offline
+answer from the snapshots, filtering by state, labels, date interval, assignee,
+author and milestone, without any network call. Snapshots are stored in
+--snapshot-dir, by default in gitlab-issue-report/snapshots under the user data
+directory, and replaced by the next save.
+
+EXAMPLES:
+  # Save the issues of the project of the current git repository
+  gitlab-issue-report snapshot save
+
+  # Save the issues of a group, then report on them offline
+  gitlab-issue-report snapshot save -g 678
+  gitlab-issue-report group -g 678 --offline --state opened --format markdown`,
+}
+
+// snapshotSaveCmd represents the snapshot save command.
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save the issues of a project or group",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if opts.offline {
+			return errSnapshotOffline
+		}
+		if len(opts.projectRefs) > 0 && opts.groupRef != "" {
+			return errSnapshotConflictIDs
+		}
+		init, err := initIssueCommand(&opts, cmd)
+		if err != nil {
+			return err
+		}
+		defer init.cancel()
+
+		store, err := openSnapshotStore(&opts)
+		if err != nil {
+			return err
+		}
+
+		scope := core.ScopeGroup
+		var ids []int64
+		if opts.groupRef != "" {
+			id, err := init.app.ResolveGroup(init.ctx, opts.groupRef)
+			if err != nil {
+				return fmt.Errorf("invalid group: %w", err)
+			}
+			ids = []int64{id}
+		} else {
+			scope = core.ScopeProject
+			if ids, err = resolveProjectIDs(init, &opts); err != nil {
+				return err
+			}
+		}
+
+		for _, id := range ids {
+			snapshot, err := init.app.SaveSnapshot(init.ctx, store, scope, id, core.WithConcurrency(opts.concurrency))
+			if err != nil {
+				return fmt.Errorf("failed to save snapshot: %w", err)
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Saved %d issues of %s %s (ID %d) in %s\n",
+				len(snapshot.Issues), scope, snapshot.Path, id, store.Dir()); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	snapshotSaveCmd.Flags().StringSliceVarP(&opts.projectRefs, "project", "p", nil,
+		"Project ID, path or web URL to save, repeat for several projects "+
+			"(auto-detected from git if neither --project nor --group is set)")
+	snapshotSaveCmd.Flags().StringVarP(&opts.groupRef, "group", "g", "", "Group ID, path or web URL to save")
+	snapshotSaveCmd.Flags().StringVar(&opts.remote, "remote", "", remoteFlagUsage)
+
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
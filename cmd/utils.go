@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"maps"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -21,18 +24,29 @@ type commandInit struct {
 	beginTime time.Time
 	endTime   time.Time
 	app       *core.App
+	ctx       context.Context    // Cancelled on Ctrl-C or when --deadline is reached
+	cancel    context.CancelFunc // Releases ctx, must be called when the command returns
 }
 
 // initIssueCommand runs the common init pipeline for project and group commands:
-// flag reconciliation, logging, environment, timeout, timezone, interval parsing,
-// and GitLab client creation.
+// config profile, flag reconciliation, logging, environment, timeout, timezone, interval parsing,
+// application creation, and the run context bounded by --deadline.
 func initIssueCommand(o *commandOptions, cmd *cobra.Command) (*commandInit, error) {
+	profile, err := applyProfile(o, cmd)
+	if err != nil {
+		return nil, err
+	}
 	if err := reconcileFlags(o); err != nil {
 		return nil, err
 	}
 	initTrace(o.logLevel)
-	if err := setupEnvironment(); err != nil {
-		return nil, err
+	if profile != "" {
+		logrus.Debugf("Using profile %q", profile)
+	}
+	if !o.offline {
+		if err := warnInsecure(cmd.ErrOrStderr(), o); err != nil {
+			return nil, err
+		}
 	}
 	applyTimeoutFromEnv(o, cmd.Flags().Changed("api-timeout"))
 	applyTimezoneFromEnv(o, cmd.Flags().Changed("timezone"))
@@ -40,32 +54,45 @@ func initIssueCommand(o *commandOptions, cmd *cobra.Command) (*commandInit, erro
 	if err != nil {
 		return nil, err
 	}
-	app, err := core.NewApp(os.Getenv("GITLAB_TOKEN"), os.Getenv("GITLAB_URI"), o.apiTimeout)
+	app, err := newApp(commandContext(cmd), o)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+		return nil, err
 	}
-	return &commandInit{beginTime: beginTime, endTime: endTime, app: app}, nil
+
+	// Flags are valid from here on, errors no longer need the usage text
+	cmd.SilenceUsage = true
+	ctx, cancel := runContext(cmd, o.deadline)
+	return &commandInit{beginTime: beginTime, endTime: endTime, app: app, ctx: ctx, cancel: cancel}, nil
 }
 
 var (
-	errGitlabTokenNotSet      = errors.New("GITLAB_TOKEN environment variable is not set")
+	errGitlabTokenNotSet = errors.New("no GitLab token: set GITLAB_TOKEN or GITLAB_TOKEN_FILE, " +
+		"a token in the config profile, or log in with glab")
 	errInvalidAPITimeoutValue = errors.New("invalid GITLAB_API_TIMEOUT value")
 )
 
-// setupEnvironment ensures required environment variables are set.
-func setupEnvironment() error {
-	// Check GitLab token
-	if len(os.Getenv("GITLAB_TOKEN")) == 0 {
-		return errGitlabTokenNotSet
-	}
-
-	// Set default GitLab URI if not provided
+// setupEnvironment ensures required environment variables are set: GITLAB_URI defaults
+// to gitlab.com and GITLAB_TOKEN is resolved from the first token source having one,
+// see resolveToken.
+func setupEnvironment(ctx context.Context, o *commandOptions) error {
+	// Set default GitLab URI if not provided, the glab token depends on the host
 	if len(os.Getenv("GITLAB_URI")) == 0 {
 		if err := os.Setenv("GITLAB_URI", "https://gitlab.com"); err != nil {
 			return fmt.Errorf("failed to set GITLAB_URI: %w", err)
 		}
 	}
 
+	token, source, err := resolveToken(ctx, o.tokenProfile, o.ignoreEnvToken)
+	if err != nil {
+		return fmt.Errorf("failed to read GitLab token: %w", err)
+	}
+	if token == "" {
+		return errGitlabTokenNotSet
+	}
+	logrus.Debugf("Using GitLab token from %s", source)
+	if err := os.Setenv("GITLAB_TOKEN", token); err != nil {
+		return fmt.Errorf("failed to set GITLAB_TOKEN: %w", err)
+	}
 	return nil
 }
 
@@ -97,33 +124,118 @@ func applyTimezoneFromEnv(o *commandOptions, flagChanged bool) {
 	}
 }
 
-// createGitlabClient creates a GitLab client with custom HTTP timeout.
-func createGitlabClient(token, uri string, timeout time.Duration) (*gitlab.Client, error) {
-	httpClient := &http.Client{
-		Timeout: timeout,
+// clientOptions returns the HTTP settings shared by every GitLab client.
+func clientOptions(o *commandOptions) core.ClientOptions {
+	return core.ClientOptions{
+		Timeout:            o.apiTimeout,
+		MaxRetries:         o.maxRetries,
+		RetryWait:          o.retryWait,
+		CACert:             o.caCert,
+		ClientCert:         o.clientCert,
+		ClientKey:          o.clientKey,
+		Proxy:              o.proxy,
+		InsecureSkipVerify: o.insecureSkipVerify,
 	}
+}
 
-	client, err := gitlab.NewClient(
-		token,
-		gitlab.WithBaseURL(uri),
-		gitlab.WithHTTPClient(httpClient),
-	)
+// warnInsecure warns on w that the server certificate is not verified with
+// --insecure-skip-verify. The warning does not depend on the log level and is meant
+// for stderr, where it cannot corrupt the report.
+func warnInsecure(w io.Writer, o *commandOptions) error {
+	if !o.insecureSkipVerify {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "WARNING: TLS certificate verification is DISABLED (--insecure-skip-verify): "+
+		"the GitLab server is not authenticated and the token can be intercepted"); err != nil {
+		return fmt.Errorf("failed to write warning: %w", err)
+	}
+	return nil
+}
+
+// newApp creates the application: on the saved snapshots with --offline, otherwise on
+// the GitLab API with the issue cache unless --no-cache is set.
+func newApp(ctx context.Context, o *commandOptions) (*core.App, error) {
+	if o.offline {
+		store, err := openSnapshotStore(o)
+		if err != nil {
+			return nil, err
+		}
+		uri := os.Getenv("GITLAB_URI")
+		if uri == "" {
+			uri = "https://gitlab.com"
+		}
+		app, err := core.NewOfflineApp(uri, store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshots: %w", err)
+		}
+		return app, nil
+	}
+
+	if err := setupEnvironment(ctx, o); err != nil {
+		return nil, err
+	}
+	app, err := core.NewApp(os.Getenv("GITLAB_TOKEN"), os.Getenv("GITLAB_URI"), clientOptions(o))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
+	if !o.noCache {
+		cache, err := openCache(o)
+		if err != nil {
+			logrus.Warnf("Issue cache disabled: %v", err)
+		} else {
+			app.SetCache(cache, o.refresh)
+		}
+	}
+	return app, nil
+}
 
-	logrus.Debugf("GitLab client created with timeout: %v", timeout)
+// openSnapshotStore returns the snapshot store in --snapshot-dir, or in the default directory.
+func openSnapshotStore(o *commandOptions) (*core.SnapshotStore, error) {
+	dir := o.snapshotDir
+	if dir == "" {
+		defaultDir, err := core.DefaultSnapshotDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshots: %w", err)
+		}
+		dir = defaultDir
+	}
+	logrus.Debugf("Using snapshots in %s", dir)
+	return core.NewSnapshotStore(dir), nil
+}
+
+// openCache returns the issue cache stored in --cache-dir, or in the default directory.
+func openCache(o *commandOptions) (*core.Cache, error) {
+	dir := o.cacheDir
+	if dir == "" {
+		defaultDir, err := core.DefaultCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open cache: %w", err)
+		}
+		dir = defaultDir
+	}
+	logrus.Debugf("Using issue cache in %s", dir)
+	return core.NewCache(dir), nil
+}
+
+// createGitlabClient creates a GitLab client with the shared HTTP settings.
+func createGitlabClient(token, uri string, clientOpts core.ClientOptions) (*gitlab.Client, error) {
+	client, err := core.NewGitlabClient(token, uri, clientOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+	}
+
+	logrus.Debugf("GitLab client created with timeout: %v, max retries: %d", clientOpts.Timeout, clientOpts.MaxRetries)
 	return client, nil
 }
 
 // getCurrentUsername fetches the username of the currently authenticated user.
-func getCurrentUsername(timeout time.Duration) (string, error) {
-	gitlabClient, err := createGitlabClient(os.Getenv("GITLAB_TOKEN"), os.Getenv("GITLAB_URI"), timeout)
+func getCurrentUsername(ctx context.Context, clientOpts core.ClientOptions) (string, error) {
+	gitlabClient, err := createGitlabClient(os.Getenv("GITLAB_TOKEN"), os.Getenv("GITLAB_URI"), clientOpts)
 	if err != nil {
 		return "", err
 	}
 
-	user, _, err := gitlabClient.Users.CurrentUser()
+	user, _, err := gitlabClient.Users.CurrentUser(gitlab.WithContext(ctx))
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch current user information: %w", err)
 	}
@@ -160,7 +272,7 @@ func parseInterval(interval, tz string) (time.Time, time.Time, error) {
 
 // buildIssueOptions creates the options for retrieving issues.
 func buildIssueOptions(
-	o *commandOptions, projectID, groupID int64, beginTime, endTime time.Time,
+	ctx context.Context, o *commandOptions, projectID, groupID int64, beginTime, endTime time.Time,
 ) ([]core.GetIssuesOption, error) {
 	var options []core.GetIssuesOption
 
@@ -175,7 +287,7 @@ func buildIssueOptions(
 
 	// Add assignee filter options
 	var err error
-	options, err = addAssigneeFilterOptions(o, options)
+	options, err = addAssigneeFilterOptions(ctx, o, options)
 	if err != nil {
 		return nil, err
 	}
@@ -183,6 +295,14 @@ func buildIssueOptions(
 	// Add labels filter options
 	options = addLabelsFilterOptions(o, options)
 
+	// Add milestone, author and search filter options
+	options = addMatchFilterOptions(o, options)
+
+	// Fetch pages in parallel; 0 keeps the default
+	if o.concurrency > 0 {
+		options = append(options, core.WithConcurrency(o.concurrency))
+	}
+
 	return options, nil
 }
 
@@ -233,10 +353,18 @@ func addStatusFilterOptions(o *commandOptions, options []core.GetIssuesOption) [
 	return options
 }
 
-// addAssigneeFilterOptions adds assignee filter options based on mine flag.
-func addAssigneeFilterOptions(o *commandOptions, options []core.GetIssuesOption) ([]core.GetIssuesOption, error) {
+// addAssigneeFilterOptions adds assignee filter options based on the mine and assignee flags.
+func addAssigneeFilterOptions(
+	ctx context.Context, o *commandOptions, options []core.GetIssuesOption,
+) ([]core.GetIssuesOption, error) {
+	switch {
+	case strings.EqualFold(o.assignee, "none"):
+		return append(options, core.WithNoAssignee()), nil
+	case o.assignee != "":
+		return append(options, core.WithAssigneeUsername(o.assignee)), nil
+	}
 	if o.mineOption {
-		username, err := getCurrentUsername(o.apiTimeout)
+		username, err := getCurrentUsername(ctx, clientOptions(o))
 		if err != nil {
 			return nil, err
 		}
@@ -251,6 +379,26 @@ func addLabelsFilterOptions(o *commandOptions, options []core.GetIssuesOption) [
 	if len(labels) > 0 {
 		options = append(options, core.WithLabels(labels))
 	}
+	if notLabels := sanitizeLabels(o.notLabels); len(notLabels) > 0 {
+		options = append(options, core.WithNotLabels(notLabels))
+	}
+	if anyLabels := sanitizeLabels(o.anyLabels); len(anyLabels) > 0 {
+		options = append(options, core.WithAnyLabels(anyLabels))
+	}
+	return options
+}
+
+// addMatchFilterOptions adds milestone, author and search filter options if set.
+func addMatchFilterOptions(o *commandOptions, options []core.GetIssuesOption) []core.GetIssuesOption {
+	if o.milestone != "" {
+		options = append(options, core.WithMilestone(o.milestone))
+	}
+	if o.author != "" {
+		options = append(options, core.WithAuthorUsername(o.author))
+	}
+	if o.search != "" {
+		options = append(options, core.WithSearch(o.search))
+	}
 	return options
 }
 
@@ -265,6 +413,51 @@ func sanitizeLabels(in []string) []string {
 	return out
 }
 
+// buildQueryParams describes the query used to fetch issues, keyed by flag name.
+// Only flags that affect the result set are included.
+func buildQueryParams(o *commandOptions, beginTime, endTime time.Time) map[string]string {
+	params := make(map[string]string)
+	if o.stateFilter != "" {
+		params["state"] = o.stateFilter
+	}
+	if o.interval != "" {
+		params["interval"] = o.interval
+		params["interval_begin"] = beginTime.Format(time.RFC3339)
+		params["interval_end"] = endTime.Format(time.RFC3339)
+		if o.createdFilter {
+			params["date_filter"] = "created"
+		} else {
+			params["date_filter"] = "updated"
+		}
+	}
+	if o.mineOption {
+		params["mine"] = "true"
+	}
+	if labels := sanitizeLabels(o.labelsFilter); len(labels) > 0 {
+		params["labels"] = strings.Join(labels, ",")
+	}
+	if labels := sanitizeLabels(o.notLabels); len(labels) > 0 {
+		params["not_labels"] = strings.Join(labels, ",")
+	}
+	if labels := sanitizeLabels(o.anyLabels); len(labels) > 0 {
+		params["any_label"] = strings.Join(labels, ",")
+	}
+	for name, value := range map[string]string{
+		"milestone": o.milestone,
+		"author":    o.author,
+		"assignee":  o.assignee,
+		"search":    o.search,
+	} {
+		if value != "" {
+			params[name] = value
+		}
+	}
+	if o.timezone != "" {
+		params["timezone"] = o.timezone
+	}
+	return params
+}
+
 // initTrace initializes the logging based on debug level.
 func initTrace(debugLevel string) {
 	// Output to stdout instead of the default stderr
@@ -283,25 +476,138 @@ func initTrace(debugLevel string) {
 }
 
 // renderIssues renders the issues based on the format flag.
-func renderIssues(issues []*gitlab.Issue, format string) error {
-	return renderIssuesWithContext(issues, nil, format)
+func renderIssues(issues []*gitlab.Issue, o *commandOptions) error {
+	return renderIssuesWithContext(issues, nil, o)
 }
 
-// renderIssuesWithContext renders issues with optional rendering context.
-func renderIssuesWithContext(issues []*gitlab.Issue, context *render.Context, format string) error {
-	var renderer render.Renderer
+// newRenderer returns the renderer for the output format, configured from the command options.
+func newRenderer(o *commandOptions) (render.Renderer, error) {
+	renderer := newFormatRenderer(o.formatOutput)
+	if o.formatOutput == "template" {
+		templateRenderer, err := newTemplateRenderer(o)
+		if err != nil {
+			return nil, err
+		}
+		renderer = templateRenderer
+	}
+	if len(o.columns) > 0 {
+		setter, ok := renderer.(render.ColumnSetter)
+		if !ok {
+			return nil, fmt.Errorf("%w: --format %s", errColumnsNotSupported, o.formatOutput)
+		}
+		columns, err := render.ParseColumns(o.columns)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidColumnsValue, err)
+		}
+		setter.SetColumns(columns)
+	}
+	if o.groupBy != "" {
+		setter, ok := renderer.(render.GroupSetter)
+		if !ok {
+			return nil, fmt.Errorf("%w: --format %s", errGroupByNotSupported, o.formatOutput)
+		}
+		grouping, err := render.ParseGroupBy(o.groupBy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidGroupByValue, err)
+		}
+		setter.SetGrouping(grouping)
+	}
+	if mode := summaryMode(o); mode != render.SummaryNone {
+		setter, ok := renderer.(render.SummarySetter)
+		if !ok {
+			return nil, fmt.Errorf("%w: --format %s", errSummaryNotSupported, o.formatOutput)
+		}
+		setter.SetSummary(mode)
+	}
+	return renderer, nil
+}
 
+// summaryMode returns the summary mode selected by --summary and --summary-only.
+func summaryMode(o *commandOptions) render.SummaryMode {
+	switch {
+	case o.summaryOnly:
+		return render.SummaryOnly
+	case o.summary:
+		return render.SummaryFooter
+	default:
+		return render.SummaryNone
+	}
+}
+
+// newFormatRenderer returns the renderer for the given output format.
+func newFormatRenderer(format string) render.Renderer {
 	switch format {
 	case "markdown":
-		renderer = render.NewMarkdownRenderer()
+		return render.NewMarkdownRenderer()
 	case "table":
-		renderer = render.NewTableRenderer()
+		return render.NewTableRenderer()
+	case "json":
+		return render.NewJSONRenderer()
+	case "ndjson":
+		return render.NewNDJSONRenderer()
+	case "csv":
+		return render.NewCSVRenderer()
+	case "tsv":
+		return render.NewTSVRenderer()
+	case "html":
+		return render.NewHTMLRenderer()
+	case "template":
+		// The template itself is loaded by newTemplateRenderer
+		return &render.TemplateRenderer{}
 	case "plain":
-		renderer = render.NewPlainRenderer(true)
+		return render.NewPlainRenderer(true)
 	default:
-		renderer = render.NewPlainRenderer(true)
+		return render.NewPlainRenderer(true)
+	}
+}
+
+// newTemplateRenderer loads the template given by --template or --template-string.
+// Dates are formatted in the --timezone location.
+func newTemplateRenderer(o *commandOptions) (*render.TemplateRenderer, error) {
+	name, text := "template-string", o.templateText
+	if o.templateFile != "" {
+		content, err := os.ReadFile(o.templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file: %w", err)
+		}
+		name, text = filepath.Base(o.templateFile), string(content)
+	}
+
+	var location *time.Location
+	if o.timezone != "" {
+		loc, err := time.LoadLocation(o.timezone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidTimezoneValue, o.timezone)
+		}
+		location = loc
 	}
 
+	renderer, err := render.NewTemplateRenderer(name, text, location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return renderer, nil
+}
+
+// isStreamingFormat reports whether the output format writes issues as pages arrive.
+func isStreamingFormat(format string) bool {
+	_, ok := newFormatRenderer(format).(render.StreamRenderer)
+	return ok
+}
+
+// renderIssuesWithContext renders issues with optional rendering context.
+func renderIssuesWithContext(issues []*gitlab.Issue, context *render.Context, o *commandOptions) error {
+	renderer, err := newRenderer(o)
+	if err != nil {
+		return err
+	}
+
+	sortKeys, err := render.ParseSortKeys(o.sortKeys)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidSortValue, err)
+	}
+	render.SortIssues(issues, sortKeys, context)
+
 	// Use context-aware rendering if context is provided
 	if context != nil {
 		if err := renderer.RenderWithContext(issues, context, os.Stdout); err != nil {
@@ -316,3 +622,103 @@ func renderIssuesWithContext(issues []*gitlab.Issue, context *render.Context, fo
 
 	return nil
 }
+
+// streamIssuesWithContext fetches the issues of each query page by page, one query
+// after the other, and writes each page as soon as it arrives. For group and instance
+// contexts, paths of projects not seen in earlier pages are resolved before the page is
+// written. If ctx is cancelled, the pages written so far are completed and a partial
+// result error is returned.
+func streamIssuesWithContext(
+	ctx context.Context,
+	app *core.App,
+	queries [][]core.GetIssuesOption,
+	context *render.Context,
+	o *commandOptions,
+) error {
+	base, err := newRenderer(o)
+	if err != nil {
+		return err
+	}
+	renderer, ok := base.(render.StreamRenderer)
+	if !ok {
+		return fmt.Errorf("%w: %s does not support streaming", errInvalidFormatValue, o.formatOutput)
+	}
+
+	count := 0
+	writePage := func(issues []*gitlab.Issue) error {
+		if context.Source == render.SourceTypeGroup || context.Source == render.SourceTypeInstance {
+			resolveMissingProjectPaths(ctx, app, issues, context)
+		}
+		if err := renderer.RenderPage(issues, context, os.Stdout); err != nil {
+			return fmt.Errorf("failed to render issues: %w", err)
+		}
+		count += len(issues)
+		return nil
+	}
+	var fetchErr error
+	for _, options := range queries {
+		if fetchErr = app.StreamIssues(ctx, writePage, options...); fetchErr != nil {
+			break
+		}
+	}
+	if fetchErr != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to get issues: %w", fetchErr)
+	}
+	if err := renderer.Finish(context, os.Stdout); err != nil {
+		return fmt.Errorf("failed to render issues: %w", err)
+	}
+	if fetchErr != nil {
+		return partialResultError(ctx, count)
+	}
+	return nil
+}
+
+// newGroupContext creates the rendering context of a group report, with the paths of
+// all projects of the group and its subgroups. If the projects cannot be listed, the
+// paths are resolved from the issues instead.
+func newGroupContext(ctx context.Context, app *core.App, groupID int64, groupPath string) *render.Context {
+	projectMap, err := app.GetGroupProjectPaths(ctx, groupID)
+	if err != nil {
+		logrus.Warnf("Failed to list group projects: %v", err)
+		projectMap = make(map[int64]string)
+	}
+	return render.NewGroupContext(groupPath, projectMap)
+}
+
+// newProjectsContext creates the rendering context of a report on several projects,
+// given by ID. Projects whose path cannot be fetched are named by their ID.
+func newProjectsContext(ctx context.Context, app *core.App, projectIDs []int64) *render.Context {
+	paths := make([]string, 0, len(projectIDs))
+	projectMap := make(map[int64]string, len(projectIDs))
+	for _, id := range projectIDs {
+		path, err := app.GetProjectPath(ctx, id)
+		if err != nil {
+			logrus.Warnf("Failed to fetch project path: %v", err)
+			path = fmt.Sprintf("ID:%d", id)
+		}
+		paths = append(paths, path)
+		projectMap[id] = path
+	}
+	return render.NewProjectsContext(paths, projectMap)
+}
+
+// resolveMissingProjectPaths adds the paths of projects not yet known by the context.
+func resolveMissingProjectPaths(ctx context.Context, app *core.App, issues []*gitlab.Issue, context *render.Context) {
+	var missing []*gitlab.Issue
+	for _, issue := range issues {
+		if _, ok := context.ProjectMap[issue.ProjectID]; !ok {
+			missing = append(missing, issue)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	projectMap, err := app.GetProjectPathsForIssues(ctx, missing)
+	if err != nil {
+		logrus.Warnf("Failed to fetch project paths: %v", err)
+	}
+	if context.ProjectMap == nil {
+		context.ProjectMap = make(map[int64]string)
+	}
+	maps.Copy(context.ProjectMap, projectMap)
+}
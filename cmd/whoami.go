@@ -14,22 +14,30 @@ var whoamiCmd = &cobra.Command{
 	Short: "Display information about the authenticated GitLab user",
 	Long:  `Display information about the authenticated GitLab user including username, full name, email, and user ID.`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		// Setup environment (validates GITLAB_TOKEN and sets default GITLAB_URI)
-		if err := setupEnvironment(); err != nil {
+		// Apply the config profile to the environment
+		if _, err := applyProfile(&opts, cmd); err != nil {
+			return err
+		}
+
+		// Setup environment (resolves GITLAB_TOKEN and sets default GITLAB_URI)
+		if err := setupEnvironment(cmd.Context(), &opts); err != nil {
 			return err
 		}
 
 		// Apply timeout from environment variable if flag not set
 		applyTimeoutFromEnv(&opts, cmd.Flags().Changed("api-timeout"))
+		if err := warnInsecure(cmd.ErrOrStderr(), &opts); err != nil {
+			return err
+		}
 
 		// Create GitLab client
-		gitlabClient, err := createGitlabClient(os.Getenv("GITLAB_TOKEN"), os.Getenv("GITLAB_URI"), opts.apiTimeout)
+		gitlabClient, err := createGitlabClient(os.Getenv("GITLAB_TOKEN"), os.Getenv("GITLAB_URI"), clientOptions(&opts))
 		if err != nil {
 			return err
 		}
 
 		// Fetch current user information
-		user, _, err := gitlabClient.Users.CurrentUser()
+		user, _, err := gitlabClient.Users.CurrentUser(gitlab.WithContext(cmd.Context()))
 		if err != nil {
 			return fmt.Errorf("failed to fetch user information: %w", err)
 		}
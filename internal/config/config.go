@@ -0,0 +1,131 @@
+// Package config loads the configuration file of gitlab-issue-report, made of named
+// profiles describing the GitLab instances to report on and of saved queries.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	errProfileNotFound = errors.New("profile not found")
+	errTokenConflict   = errors.New("only one of token, token_file and token_command can be set")
+)
+
+// Config is the content of the configuration file.
+//
+//	default_profile: work
+//	profiles:
+//	  work:
+//	    uri: https://gitlab.example.com
+//	    token_command: pass show gitlab/work
+//	    timeout: 1m
+//	    timezone: Europe/Paris
+//	    format: markdown
+//	    group: 678
+type Config struct {
+	DefaultProfile string              `yaml:"default_profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles,omitempty"`
+	Queries        map[string]Query    `yaml:"queries,omitempty"`
+}
+
+// Profile holds the settings of a GitLab instance. Empty fields are left to the
+// environment variables and built-in defaults.
+type Profile struct {
+	URI          string        `yaml:"uri,omitempty"`           // GitLab URI, as GITLAB_URI
+	Token        string        `yaml:"token,omitempty"`         // Access token, as GITLAB_TOKEN
+	TokenFile    string        `yaml:"token_file,omitempty"`    // File holding the access token
+	TokenCommand string        `yaml:"token_command,omitempty"` // Command printing the access token
+	Timeout      time.Duration `yaml:"timeout,omitempty"`       // API timeout, as GITLAB_API_TIMEOUT
+	Timezone     string        `yaml:"timezone,omitempty"`      // Timezone, as GITLAB_TIMEZONE
+	Format       string        `yaml:"format,omitempty"`        // Default output format
+	Group        string        `yaml:"group,omitempty"`         // Default group of the group command, ID or path
+
+	CACert             string `yaml:"ca_cert,omitempty"`              // CA bundle, as --ca-cert
+	ClientCert         string `yaml:"client_cert,omitempty"`          // Client certificate, as --client-cert
+	ClientKey          string `yaml:"client_key,omitempty"`           // Client key, as --client-key
+	Proxy              string `yaml:"proxy,omitempty"`                // Proxy URL, as --proxy
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"` // As --insecure-skip-verify
+}
+
+// DefaultPath returns the default location of the configuration file,
+// $XDG_CONFIG_HOME/gitlab-issue-report/config.yaml on Linux.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "gitlab-issue-report", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path. Unknown keys are reported as errors
+// so that typos do not go unnoticed. A missing file is reported as fs.ErrNotExist.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	for name, profile := range cfg.Profiles {
+		if profile == nil {
+			cfg.Profiles[name] = &Profile{}
+			continue
+		}
+		if countSet(profile.Token, profile.TokenFile, profile.TokenCommand) > 1 {
+			return nil, fmt.Errorf("profile %q: %w", name, errTokenConflict)
+		}
+	}
+	return &cfg, nil
+}
+
+// Profile returns the named profile, or the default profile when name is empty.
+// It returns nil without error when no name is given and no default profile is set.
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (available: %s)", errProfileNotFound, name, strings.Join(c.ProfileNames(), ", "))
+	}
+	return profile, nil
+}
+
+// ProfileNames returns the names of the profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// countSet returns the number of non-empty values.
+func countSet(values ...string) int {
+	count := 0
+	for _, value := range values {
+		if value != "" {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+default_profile: work
+profiles:
+  work:
+    uri: https://gitlab.example.com
+    token_command: pass show gitlab
+    timeout: 1m
+    timezone: Europe/Paris
+    format: markdown
+    group: 678
+  public:
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	profile, err := cfg.Profile("")
+	if err != nil {
+		t.Fatalf("Profile(\"\") error = %v", err)
+	}
+	want := Profile{
+		URI:          "https://gitlab.example.com",
+		TokenCommand: "pass show gitlab",
+		Timeout:      time.Minute,
+		Timezone:     "Europe/Paris",
+		Format:       "markdown",
+		Group:        "678",
+	}
+	if *profile != want {
+		t.Errorf("default profile = %+v, want %+v", *profile, want)
+	}
+
+	if profile, err := cfg.Profile("public"); err != nil || *profile != (Profile{}) {
+		t.Errorf("Profile(\"public\") = %+v, %v, want an empty profile", profile, err)
+	}
+
+	_, err = cfg.Profile("missing")
+	if !errors.Is(err, errProfileNotFound) || !strings.Contains(err.Error(), "public, work") {
+		t.Errorf("Profile(\"missing\") error = %v, want profile not found listing the profiles", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "unknown key",
+			content: "profiles:\n  work:\n    url: https://gitlab.example.com\n",
+			want:    "field url not found",
+		},
+		{
+			name:    "invalid timeout",
+			content: "profiles:\n  work:\n    timeout: soon\n",
+			want:    "soon",
+		},
+		{
+			name:    "token and token command",
+			content: "profiles:\n  work:\n    token: abc\n    token_command: pass show gitlab\n",
+			want:    "only one of token, token_file and token_command",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Load() of a missing file error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestNoDefaultProfile(t *testing.T) {
+	cfg, err := Load(writeConfig(t, ""))
+	if err != nil {
+		t.Fatalf("Load() of an empty file error = %v", err)
+	}
+	if profile, err := cfg.Profile(""); profile != nil || err != nil {
+		t.Errorf("Profile(\"\") without default = %+v, %v, want nil", profile, err)
+	}
+}
+
+func TestRunTokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	token, err := RunTokenCommand(t.Context(), "printf 'glpat-123\\nlogin: me\\n'")
+	if err != nil || token != "glpat-123" {
+		t.Errorf("RunTokenCommand() = %q, %v, want the first line", token, err)
+	}
+
+	if _, err := RunTokenCommand(t.Context(), "true"); !errors.Is(err, errEmptyToken) {
+		t.Errorf("RunTokenCommand() without output error = %v, want errEmptyToken", err)
+	}
+
+	_, err = RunTokenCommand(t.Context(), "echo locked >&2; exit 1")
+	if err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("RunTokenCommand() failing error = %v, want the standard error", err)
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("checks POSIX permissions")
+	}
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("glpat-123\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := ReadTokenFile(path); err != nil || token != "glpat-123" {
+		t.Errorf("ReadTokenFile() = %q, %v, want the first line", token, err)
+	}
+
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTokenFile(path); !errors.Is(err, errTokenFileShared) {
+		t.Errorf("ReadTokenFile() of a group-readable file error = %v, want errTokenFileShared", err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTokenFile(empty); !errors.Is(err, errEmptyTokenFile) {
+		t.Errorf("ReadTokenFile() of an empty file error = %v, want errEmptyTokenFile", err)
+	}
+}
+
+func TestGlabToken(t *testing.T) {
+	path := writeConfig(t, `
+git_protocol: ssh
+hosts:
+  gitlab.com:
+    token: glpat-public
+    api_protocol: https
+  gitlab.example.com:8443:
+    token: glpat-work
+`)
+	tests := map[string]string{
+		"gitlab.com":              "glpat-public",
+		"gitlab.example.com:8443": "glpat-work",
+		"gitlab.other.com":        "",
+	}
+	for host, want := range tests {
+		if token, err := GlabToken(path, host); err != nil || token != want {
+			t.Errorf("GlabToken(%q) = %q, %v, want %q", host, token, err, want)
+		}
+	}
+
+	if token, err := GlabToken(filepath.Join(t.TempDir(), "config.yml"), "gitlab.com"); err != nil || token != "" {
+		t.Errorf("GlabToken() without glab config = %q, %v, want no token", token, err)
+	}
+}
+
+func TestSaveAndDeleteQuery(t *testing.T) {
+	path := writeConfig(t, `# Instances
+profiles:
+  work:
+    uri: https://gitlab.example.com # self-hosted
+`)
+	query := Query{"group": 678, "labels": []string{"bug", "regression"}, "updated": true, "interval": "/-7/ ::"}
+	if err := SaveQuery(path, "weekly-bugs", query); err != nil {
+		t.Fatalf("SaveQuery() error = %v", err)
+	}
+	if err := SaveQuery(path, "mine", Query{"mine": true}); err != nil {
+		t.Fatalf("SaveQuery() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# Instances") || !strings.Contains(string(data), "# self-hosted") {
+		t.Errorf("SaveQuery() should preserve comments, got:\n%s", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("SaveQuery() file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Profiles["work"].URI != "https://gitlab.example.com" {
+		t.Error("SaveQuery() should keep the profiles")
+	}
+	saved, err := cfg.Query("weekly-bugs")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got := saved.Value("labels"); got != "bug,regression" {
+		t.Errorf("Value(labels) = %q, want comma-separated labels", got)
+	}
+	if got := saved.Value("group"); got != "678" {
+		t.Errorf("Value(group) = %q", got)
+	}
+	if names := saved.Names(); !slices.Equal(names, []string{"group", "interval", "labels", "updated"}) {
+		t.Errorf("Names() = %v", names)
+	}
+
+	if err := DeleteQuery(path, "weekly-bugs"); err != nil {
+		t.Fatalf("DeleteQuery() error = %v", err)
+	}
+	if err := DeleteQuery(path, "weekly-bugs"); !errors.Is(err, errQueryNotFound) {
+		t.Errorf("DeleteQuery() of a deleted query error = %v, want errQueryNotFound", err)
+	}
+	cfg, err = Load(path)
+	if err != nil || !slices.Equal(cfg.QueryNames(), []string{"mine"}) {
+		t.Errorf("queries after delete = %v, %v", cfg.QueryNames(), err)
+	}
+}
+
+func TestSaveQueryCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gitlab-issue-report", "config.yaml")
+	if err := SaveQuery(path, "closed", Query{"state": "closed"}); err != nil {
+		t.Fatalf("SaveQuery() error = %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if query, err := cfg.Query("closed"); err != nil || query.Value("state") != "closed" {
+		t.Errorf("Query() = %v, %v", query, err)
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	errQueryNotFound = errors.New("query not found")
+	errInvalidConfig = errors.New("config file must be a YAML mapping")
+)
+
+// Query is a saved report definition, mapping flag names to their values, e.g.
+//
+//	queries:
+//	  weekly-bugs:
+//	    group: 678
+//	    labels: [bug]
+//	    interval: "/-7/ ::"
+//	    format: markdown
+//
+// Values are scalars or lists of scalars, for flags accepting several values.
+type Query map[string]any
+
+// Names returns the flag names of the query, sorted.
+func (q Query) Names() []string {
+	names := make([]string, 0, len(q))
+	for name := range q {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Value returns the value of a flag of the query in the syntax of the command line,
+// lists being comma-separated.
+func (q Query) Value(name string) string {
+	switch values := q[name].(type) {
+	case []string:
+		return strings.Join(values, ",")
+	case []any:
+		texts := make([]string, 0, len(values))
+		for _, value := range values {
+			texts = append(texts, fmt.Sprint(value))
+		}
+		return strings.Join(texts, ",")
+	default:
+		return fmt.Sprint(values)
+	}
+}
+
+// Query returns the named query.
+func (c *Config) Query(name string) (Query, error) {
+	query, ok := c.Queries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (available: %s)", errQueryNotFound, name, strings.Join(c.QueryNames(), ", "))
+	}
+	return query, nil
+}
+
+// QueryNames returns the names of the queries, sorted.
+func (c *Config) QueryNames() []string {
+	names := make([]string, 0, len(c.Queries))
+	for name := range c.Queries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SaveQuery adds the query to the config file at path, replacing the query of the
+// same name. The file is created if needed; comments and the order of the existing
+// settings are preserved.
+func SaveQuery(path, name string, query Query) error {
+	return editFile(path, func(root *yaml.Node) error {
+		var value yaml.Node
+		if err := value.Encode(query); err != nil {
+			return fmt.Errorf("failed to encode query: %w", err)
+		}
+
+		queries := mappingValue(root, "queries")
+		if queries == nil || queries.Kind != yaml.MappingNode {
+			if queries == nil {
+				queries = &yaml.Node{}
+				root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "queries"}, queries)
+			}
+			*queries = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		if existing := mappingValue(queries, name); existing != nil {
+			*existing = value
+			return nil
+		}
+		queries.Content = append(queries.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, &value)
+		return nil
+	})
+}
+
+// DeleteQuery removes the named query from the config file at path.
+func DeleteQuery(path, name string) error {
+	return editFile(path, func(root *yaml.Node) error {
+		queries := mappingValue(root, "queries")
+		if queries != nil {
+			for i := 0; i+1 < len(queries.Content); i += 2 {
+				if queries.Content[i].Value == name {
+					queries.Content = slices.Delete(queries.Content, i, i+2)
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%w: %q", errQueryNotFound, name)
+	})
+}
+
+// mappingValue returns the value of key in a mapping node, nil if absent.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// editFile applies edit to the top-level mapping of the config file at path and
+// writes the file back. The file is created with owner-only permissions, as it may
+// hold tokens, and replaced atomically.
+func editFile(path string, edit func(root *yaml.Node) error) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%w: %s", errInvalidConfig, path)
+	}
+	if err := edit(root); err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	return writeFile(path, out.Bytes())
+}
+
+// writeFile replaces the file at path with data, creating its directory if needed.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	errEmptyToken      = errors.New("token command printed no token")
+	errEmptyTokenFile  = errors.New("token file is empty")
+	errTokenFileShared = errors.New("token file is accessible by other users")
+)
+
+// RunTokenCommand runs command through the system shell and returns the first line
+// of its standard output, e.g. for "pass show gitlab". The standard error of the
+// command is included in the error when it fails.
+func RunTokenCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command %q failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	token, _, _ := strings.Cut(string(out), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("%w: %q", errEmptyToken, command)
+	}
+	return token, nil
+}
+
+// ReadTokenFile returns the first line of the file at path. The file must not be
+// readable or writable by other users, like SSH keys.
+func ReadTokenFile(path string) (string, error) {
+	if err := CheckPrivate(path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token, _, _ := strings.Cut(string(data), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("%w: %s", errEmptyTokenFile, path)
+	}
+	return token, nil
+}
+
+// CheckPrivate returns an error when the file at path can be accessed by users other
+// than its owner. Permissions are not checked on Windows, which has no such modes.
+func CheckPrivate(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read token file: %w", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("%w: %s has mode %04o, run chmod 600 %s",
+			errTokenFileShared, path, info.Mode().Perm(), path)
+	}
+	return nil
+}
+
+// glabConfig is the part of the glab CLI configuration holding the tokens.
+type glabConfig struct {
+	Hosts map[string]struct {
+		Token string `yaml:"token"`
+	} `yaml:"hosts"`
+}
+
+// GlabConfigPath returns the location of the configuration file of the glab CLI:
+// $GLAB_CONFIG_DIR/config.yml, or glab-cli/config.yml under $XDG_CONFIG_HOME or ~/.config.
+func GlabConfigPath() (string, error) {
+	if dir := os.Getenv("GLAB_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "config.yml"), nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate glab config: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "glab-cli", "config.yml"), nil
+}
+
+// GlabToken returns the token stored by the glab CLI in the file at path for host,
+// e.g. "gitlab.com". It returns an empty token when the file does not exist or has no
+// token for host, for instance because glab keeps it in the system keyring.
+func GlabToken(path, host string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read glab config: %w", err)
+	}
+	var cfg glabConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return "", fmt.Errorf("failed to parse glab config %s: %w", path, err)
+	}
+	return strings.TrimSpace(cfg.Hosts[host].Token), nil
+}
@@ -0,0 +1,414 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// cacheVersion is the version of the cache file format. Files of another version are ignored.
+const cacheVersion = 1
+
+// Scopes of the cache entries.
+const (
+	ScopeProject = "project"
+	ScopeGroup   = "group"
+)
+
+var errCacheVersion = errors.New("unsupported cache file version")
+
+// Cache stores the issues of projects and groups on disk, one file per project or
+// group, GitLab instance and account, so that later runs only download the issues
+// updated since the last sync. Accounts are kept apart as they may not see the same
+// issues: an entry is only read back with the token it was synced with.
+type Cache struct {
+	dir string
+}
+
+// CacheEntry describes a cached project or group.
+type CacheEntry struct {
+	Host     string    // GitLab instance
+	Account  string    // Key of the token the entry was synced with, empty for older entries
+	Scope    string    // ScopeProject or ScopeGroup
+	ID       int64     // Project or group ID
+	Issues   int       // Number of cached issues
+	Size     int64     // Size of the cache file in bytes
+	SyncedAt time.Time // Time of the last sync with GitLab
+	path     string
+}
+
+// cacheFile is the on-disk format of a cache entry.
+type cacheFile struct {
+	Version    int             `json:"version"`
+	SyncedAt   time.Time       `json:"synced_at"`
+	FullSyncAt time.Time       `json:"full_sync_at"` // Time of the last download of every issue
+	Cursor     time.Time       `json:"cursor"`       // Most recent updated_at of the cached issues
+	Issues     []*gitlab.Issue `json:"issues"`
+}
+
+// NewCache creates a cache stored in dir. The directory is created on the first write.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// DefaultCacheDir returns the default cache directory, under the user cache directory.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "gitlab-issue-report"), nil
+}
+
+// Dir returns the directory of the cache.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Entries lists the cached projects and groups. Unreadable files are skipped.
+func (c *Cache) Entries() ([]CacheEntry, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]CacheEntry, 0, len(files))
+	for _, entry := range files {
+		content, err := readCacheFile(entry.path)
+		if err != nil {
+			logrus.Warnf("Skipping cache file %s: %v", entry.path, err)
+			continue
+		}
+		entry.Issues = len(content.Issues)
+		entry.SyncedAt = content.SyncedAt
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Prune removes the entries not synced since olderThan before now, as well as
+// unreadable cache files, and returns them.
+func (c *Cache) Prune(olderThan time.Duration, now time.Time) ([]CacheEntry, error) {
+	return c.remove(func(entry CacheEntry) bool {
+		content, err := readCacheFile(entry.path)
+		return err != nil || content.SyncedAt.Before(now.Add(-olderThan))
+	})
+}
+
+// Clear removes all the cache files and returns them. Other files of the
+// directory are left untouched.
+func (c *Cache) Clear() ([]CacheEntry, error) {
+	return c.remove(func(CacheEntry) bool { return true })
+}
+
+// files lists the cache files, without reading them.
+func (c *Cache) files() ([]CacheEntry, error) {
+	hosts, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []CacheEntry
+	for _, host := range hosts {
+		if !host.IsDir() {
+			continue
+		}
+		hostName, account, _ := strings.Cut(host.Name(), "@")
+		files, err := os.ReadDir(filepath.Join(c.dir, host.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache directory: %w", err)
+		}
+		for _, file := range files {
+			scope, id, ok := parseCacheFileName(file.Name())
+			if !ok {
+				continue
+			}
+			entry := CacheEntry{
+				Host:    hostName,
+				Account: account,
+				Scope:   scope,
+				ID:      id,
+				path:    filepath.Join(c.dir, host.Name(), file.Name()),
+			}
+			if info, err := file.Info(); err == nil {
+				entry.Size = info.Size()
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// remove removes the cache files selected by match, then the host directories left empty.
+func (c *Cache) remove(match func(CacheEntry) bool) ([]CacheEntry, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	var removed []CacheEntry
+	for _, entry := range files {
+		if !match(entry) {
+			continue
+		}
+		if err := os.Remove(entry.path); err != nil {
+			return removed, fmt.Errorf("failed to remove cache file: %w", err)
+		}
+		removed = append(removed, entry)
+	}
+
+	for _, dir := range slices.Compact(dirsOf(removed)) {
+		// Fails, as intended, when the directory is not empty
+		_ = os.Remove(dir)
+	}
+	return removed, nil
+}
+
+// dirsOf returns the directory of each entry.
+func dirsOf(entries []CacheEntry) []string {
+	dirs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		dirs = append(dirs, filepath.Dir(entry.path))
+	}
+	return dirs
+}
+
+// path returns the path of the cache file of a project or group of a GitLab instance,
+// synced with the token of account.
+func (c *Cache) path(host, account, scope string, id int64) string {
+	return filepath.Join(c.dir, hostDir(host)+"@"+account, fmt.Sprintf("%s-%d.json", scope, id))
+}
+
+// hostDir returns the directory name of a GitLab host, the port separator being
+// replaced as it is not allowed in file names on every system.
+func hostDir(host string) string {
+	return strings.NewReplacer(":", "_", "/", "_", "\\", "_", "@", "_").Replace(host)
+}
+
+// accountKey returns the key of the account of a token in the cache: a hash of the
+// token, so that the token itself is not written to disk.
+func accountKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// load reads the cache entry of a project or group. A missing entry is returned empty.
+func (c *Cache) load(host, account, scope string, id int64) (*cacheFile, error) {
+	content, err := readCacheFile(c.path(host, account, scope, id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &cacheFile{Version: cacheVersion}, nil
+	}
+	return content, err
+}
+
+// save writes the cache entry of a project or group. The file is replaced atomically,
+// so that concurrent runs never read a partially written entry.
+func (c *Cache) save(host, account, scope string, id int64, content *cacheFile) error {
+	path := c.path(host, account, scope, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	content.Version = cacheVersion
+	if err := json.NewEncoder(tmp).Encode(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// readCacheFile decodes a cache file.
+func readCacheFile(path string) (*cacheFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+	var content cacheFile
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to decode cache file: %w", err)
+	}
+	if content.Version != cacheVersion {
+		return nil, fmt.Errorf("%w: %d", errCacheVersion, content.Version)
+	}
+	return &content, nil
+}
+
+// parseCacheFileName returns the scope and ID of a cache file name such as "group-42.json".
+func parseCacheFileName(name string) (string, int64, bool) {
+	base, ok := strings.CutSuffix(name, ".json")
+	if !ok {
+		return "", 0, false
+	}
+	scope, idText, ok := strings.Cut(base, "-")
+	if !ok || (scope != ScopeProject && scope != ScopeGroup) {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(idText, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return scope, id, true
+}
+
+// SetCache makes StreamIssues and GetIssues answer queries from cache, downloading
+// only the issues updated since the last sync. The first sync of a project or group,
+// and a full sync every fullSyncInterval, download all its issues whatever the filters,
+// which costs as much as an uncached report with --state all. With refresh, the cached
+// issues of the queried project or group are discarded and downloaded again.
+func (a *App) SetCache(cache *Cache, refresh bool) {
+	a.cache = cache
+	a.refreshCache = refresh
+}
+
+// fullSyncInterval is the time after which all the issues of a cache entry are
+// downloaded again, dropping those deleted or moved out of the project or group, which
+// the incremental sync cannot see.
+const fullSyncInterval = 7 * 24 * time.Hour
+
+// streamCachedIssues syncs the cache entry of the project or group selected by g and
+// passes the issues matching g to fn: first the pages downloaded by the sync, as they
+// arrive, then the cached issues not downloaded again, by pages, most recently created
+// first. Each issue is thus passed once, in its current version. When the sync is
+// interrupted, the issues downloaded so far are saved and the cached issues are still
+// passed to fn before the error is returned.
+func (a *App) streamCachedIssues(ctx context.Context, g *GetIssues, fn IssuePageFunc) error {
+	scope, id := ScopeProject, g.ProjectID
+	if g.ProjectID == 0 {
+		scope, id = ScopeGroup, g.GroupID
+	}
+	entry := a.loadCacheEntry(scope, id)
+	cached := entry.Issues
+	full := entry.FullSyncAt.IsZero() || time.Since(entry.FullSyncAt) > fullSyncInterval
+
+	// Every issue is cached whatever the filters, which are applied locally
+	query := &GetIssues{ProjectID: g.ProjectID, GroupID: g.GroupID, Concurrency: g.Concurrency}
+	if !full {
+		query.FilterUpdatedAtAfter = entry.Cursor
+	}
+	syncedAt := time.Now()
+	var delta []*gitlab.Issue
+	var fnErr error
+	syncErr := a.streamIssues(ctx, query, func(issues []*gitlab.Issue) error {
+		delta = append(delta, issues...)
+		page := make([]*gitlab.Issue, 0, len(issues))
+		for _, issue := range issues {
+			if g.matches(issue) {
+				page = append(page, issue)
+			}
+		}
+		if len(page) > 0 {
+			fnErr = fn(page)
+		}
+		return fnErr
+	})
+	logrus.Debugf("Cache of %s %d: %d cached issues, %d downloaded (full sync: %v, since %v)",
+		scope, id, len(cached), len(delta), full, query.FilterUpdatedAtAfter)
+
+	complete := syncErr == nil
+	applySync(entry, delta, syncedAt, full, complete)
+	if err := a.cache.save(a.host, a.account, scope, id, entry); err != nil {
+		logrus.Warnf("Failed to update cache of %s %d: %v", scope, id, err)
+	}
+	switch {
+	case fnErr != nil:
+		return fnErr
+	case !complete && ctx.Err() == nil:
+		return syncErr
+	case complete && full:
+		return nil // The cached issues not downloaded again no longer exist
+	}
+
+	downloaded := make(map[int64]bool, len(delta))
+	for _, issue := range delta {
+		downloaded[issue.ID] = true
+	}
+	rest := make([]*gitlab.Issue, 0, len(cached))
+	for _, issue := range cached {
+		if !downloaded[issue.ID] {
+			rest = append(rest, issue)
+		}
+	}
+	if err := emitIssues(filterIssues(rest, g), fn); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+// loadCacheEntry returns the cache entry of a project or group, empty when it does not
+// exist, cannot be read or is to be refreshed.
+func (a *App) loadCacheEntry(scope string, id int64) *cacheFile {
+	if a.refreshCache {
+		return &cacheFile{}
+	}
+	entry, err := a.cache.load(a.host, a.account, scope, id)
+	if err != nil {
+		logrus.Warnf("Ignoring cache of %s %d: %v", scope, id, err)
+		return &cacheFile{}
+	}
+	return entry
+}
+
+// applySync updates a cache entry with the issues downloaded by a sync started at
+// syncedAt. A complete full sync replaces the cached issues, other syncs merge them.
+// The cursor only advances when the sync is complete: an interrupted sync may have
+// missed issues updated since the cursor, which the next sync downloads.
+func applySync(entry *cacheFile, delta []*gitlab.Issue, syncedAt time.Time, full, complete bool) {
+	if full && complete {
+		entry.Issues = delta
+		entry.FullSyncAt = syncedAt
+		entry.Cursor = time.Time{}
+	} else {
+		entry.Issues = mergeIssues(entry.Issues, delta)
+	}
+	if !complete {
+		return
+	}
+	entry.SyncedAt = syncedAt
+	for _, issue := range delta {
+		if issue.UpdatedAt != nil && issue.UpdatedAt.After(entry.Cursor) {
+			entry.Cursor = *issue.UpdatedAt
+		}
+	}
+}
+
+// mergeIssues replaces the cached issues by their updated version and appends the new ones.
+func mergeIssues(cached, updated []*gitlab.Issue) []*gitlab.Issue {
+	index := make(map[int64]int, len(cached))
+	for i, issue := range cached {
+		index[issue.ID] = i
+	}
+	for _, issue := range updated {
+		if i, ok := index[issue.ID]; ok {
+			cached[i] = issue
+			continue
+		}
+		index[issue.ID] = len(cached)
+		cached = append(cached, issue)
+	}
+	return cached
+}
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestCacheIncrementalSync(t *testing.T) {
+	fake := &fakeGitLab{}
+	fake.add(3)
+	app := newTestApp(t, fake)
+	cache := NewCache(t.TempDir())
+	app.SetCache(cache, false)
+
+	issues, err := app.GetIssues(t.Context(), WithProjectID(1), WithOpenedIssues())
+	if err != nil || !slices.Equal(issueIDs(issues), []int64{3, 2, 1}) {
+		t.Fatalf("first GetIssues() = %v, %v, want issues 3, 2 and 1", issueIDs(issues), err)
+	}
+	first := fake.lastRequests(1)[0]
+	if first.Has("updated_after") || first.Has("state") {
+		t.Errorf("first sync sent %v, want every issue whatever the filters", first)
+	}
+
+	// Issue 2 is closed and issue 4 created after the first sync
+	fake.update(2, baseTime.Add(10*time.Hour), func(issue *gitlab.Issue) { issue.State = "closed" })
+	fake.add(1)
+
+	issues, err = app.GetIssues(t.Context(), WithProjectID(1), WithOpenedIssues())
+	if err != nil || !slices.Equal(issueIDs(issues), []int64{4, 3, 1}) {
+		t.Errorf("second GetIssues() = %v, %v, want issues 4, 3 and 1", issueIDs(issues), err)
+	}
+	cursor, err := time.Parse(time.RFC3339, fake.lastRequests(1)[0].Get("updated_after"))
+	if err != nil || !cursor.Equal(baseTime.Add(3*time.Hour)) {
+		t.Errorf("second sync updated_after = %v, %v, want the last updated_at of the first sync", cursor, err)
+	}
+
+	issues, err = app.GetIssues(t.Context(), WithProjectID(1), WithClosedIssues())
+	if err != nil || len(issues) != 1 || issues[0].ID != 2 || issues[0].State != "closed" {
+		t.Errorf("GetIssues() of closed issues = %v, %v, want the updated issue 2", issueIDs(issues), err)
+	}
+	entry, err := cache.load(app.host, app.account, ScopeProject, 1)
+	if err != nil || len(entry.Issues) != 4 || !entry.Cursor.Equal(baseTime.Add(10*time.Hour)) {
+		t.Errorf("cache entry = %d issues, cursor %v, %v, want 4 issues and the cursor advanced",
+			len(entry.Issues), entry.Cursor, err)
+	}
+}
+
+func TestCacheFullSync(t *testing.T) {
+	fake := &fakeGitLab{}
+	fake.add(3)
+	app := newTestApp(t, fake)
+	cache := NewCache(t.TempDir())
+	app.SetCache(cache, false)
+	if _, err := app.GetIssues(t.Context(), WithProjectID(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Issue 2 is moved to another project, which the incremental sync cannot see
+	fake.issues = slices.DeleteFunc(fake.issues, func(issue *gitlab.Issue) bool { return issue.ID == 2 })
+	issues, err := app.GetIssues(t.Context(), WithProjectID(1))
+	if err != nil || len(issues) != 3 {
+		t.Fatalf("GetIssues() = %v, %v, want the 3 cached issues until the next full sync", issueIDs(issues), err)
+	}
+
+	entry, err := cache.load(app.host, app.account, ScopeProject, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.FullSyncAt = time.Now().Add(-fullSyncInterval - time.Hour)
+	if err := cache.save(app.host, app.account, ScopeProject, 1, entry); err != nil {
+		t.Fatal(err)
+	}
+	issues, err = app.GetIssues(t.Context(), WithProjectID(1))
+	if err != nil || !slices.Equal(issueIDs(issues), []int64{3, 1}) {
+		t.Errorf("GetIssues() after a full sync = %v, %v, want issues 3 and 1", issueIDs(issues), err)
+	}
+	if request := fake.lastRequests(1)[0]; request.Has("updated_after") {
+		t.Errorf("full sync sent updated_after %s", request.Get("updated_after"))
+	}
+	if entry, err := cache.load(app.host, app.account, ScopeProject, 1); err != nil || len(entry.Issues) != 2 {
+		t.Errorf("cache entry after a full sync = %v, want issues 3 and 1", err)
+	}
+}
+
+func TestCacheStreamsAndKeepsInterruptedSync(t *testing.T) {
+	fake := &fakeGitLab{}
+	fake.add(150)
+	app := newTestApp(t, fake)
+	cache := NewCache(t.TempDir())
+	app.SetCache(cache, false)
+	if _, err := app.GetIssues(t.Context(), WithProjectID(1)); err != nil {
+		t.Fatal(err)
+	}
+	before, err := cache.load(app.host, app.account, ScopeProject, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 120 issues updated: the sync takes two pages, interrupted after the first one
+	for id := int64(31); id <= 150; id++ {
+		fake.update(id, baseTime.Add(200*time.Hour), func(issue *gitlab.Issue) { issue.Title = "updated" })
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	var pages [][]*gitlab.Issue
+	err = app.StreamIssues(ctx, func(issues []*gitlab.Issue) error {
+		if len(pages) == 0 && issues[0].Title != "updated" {
+			t.Error("the first page passed should be the first page downloaded")
+		}
+		pages = append(pages, issues)
+		cancel()
+		return nil
+	}, WithProjectID(1), WithConcurrency(1))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamIssues() interrupted error = %v, want context.Canceled", err)
+	}
+
+	seen := make(map[int64]bool)
+	for issue := range slices.Values(slices.Concat(pages...)) {
+		if seen[issue.ID] {
+			t.Errorf("issue %d passed twice", issue.ID)
+		}
+		seen[issue.ID] = true
+	}
+	if len(seen) != 150 || len(pages[0]) != 100 {
+		t.Errorf("interrupted sync passed %d issues, first page %d, want the downloaded page then the cached issues",
+			len(seen), len(pages[0]))
+	}
+
+	entry, err := cache.load(app.host, app.account, ScopeProject, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := 0
+	for _, issue := range entry.Issues {
+		if issue.Title == "updated" {
+			updated++
+		}
+	}
+	if updated != 100 || !entry.Cursor.Equal(before.Cursor) || !entry.SyncedAt.Equal(before.SyncedAt) {
+		t.Errorf("cache after an interrupted sync: %d updated issues, cursor %v, want 100 and the cursor %v kept",
+			updated, entry.Cursor, before.Cursor)
+	}
+}
+
+func TestCacheKeepsAccountsApart(t *testing.T) {
+	fake := &fakeGitLab{}
+	fake.add(3)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	cache := NewCache(t.TempDir())
+	newApp := func(token string) *App {
+		app, err := NewApp(token, server.URL, ClientOptions{RetryWait: time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.SetCache(cache, false)
+		return app
+	}
+
+	if _, err := newApp("personal-token").GetIssues(t.Context(), WithProjectID(1)); err != nil {
+		t.Fatal(err)
+	}
+	// A bot token sees fewer issues: the cache of the personal token must not leak to it
+	fake.mu.Lock()
+	fake.issues = fake.issues[:1]
+	fake.mu.Unlock()
+	issues, err := newApp("bot-token").GetIssues(t.Context(), WithProjectID(1))
+	if err != nil || !slices.Equal(issueIDs(issues), []int64{1}) {
+		t.Errorf("GetIssues() with another token = %v, %v, want only issue 1", issueIDs(issues), err)
+	}
+	if request := fake.lastRequests(1)[0]; request.Has("updated_after") {
+		t.Errorf("sync with another token sent %v, want a full sync", request)
+	}
+
+	entries, err := cache.Entries()
+	if err != nil || len(entries) != 2 || entries[0].Account == entries[1].Account {
+		t.Fatalf("Entries() = %+v, %v, want an entry per token", entries, err)
+	}
+	for _, entry := range entries {
+		if entry.Host != hostDir(newApp("x").host) || strings.Contains(entry.Account, "token") {
+			t.Errorf("entry = %+v, want the host and a hash of the token", entry)
+		}
+	}
+}
+
+func TestCacheKeepsAPIOrder(t *testing.T) {
+	fake := &fakeGitLab{}
+	fake.add(4)
+	app := newTestApp(t, fake)
+	app.SetCache(NewCache(t.TempDir()), false)
+	if _, err := app.GetIssues(t.Context(), WithProjectID(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The oldest issue is updated: the sync downloads it before the cached ones
+	fake.update(1, baseTime.Add(10*time.Hour), func(issue *gitlab.Issue) { issue.Title = "updated" })
+	issues, err := app.GetIssues(t.Context(), WithProjectID(1))
+	if err != nil || !slices.Equal(issueIDs(issues), []int64{4, 3, 2, 1}) || issues[3].Title != "updated" {
+		t.Errorf("GetIssues() = %v, %v, want issues 4 to 1, most recently created first", issueIDs(issues), err)
+	}
+}
+
+func TestMergeIssues(t *testing.T) {
+	issue := func(id int64, title string) *gitlab.Issue { return &gitlab.Issue{ID: id, Title: title} }
+	merged := mergeIssues(
+		[]*gitlab.Issue{issue(1, "one"), issue(2, "two")},
+		[]*gitlab.Issue{issue(2, "two, edited"), issue(3, "three")},
+	)
+	if !slices.Equal(issueIDs(merged), []int64{1, 2, 3}) || merged[1].Title != "two, edited" {
+		t.Errorf("mergeIssues() = %v, want issue 2 replaced and issue 3 appended", issueIDs(merged))
+	}
+}
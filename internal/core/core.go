@@ -2,33 +2,52 @@
 package core
 
 import (
+	"errors"
 	"fmt"
-	"net/http"
-	"time"
+	"net/url"
+	"strings"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
+var errInvalidGitlabURI = errors.New("invalid GitLab URI")
+
 // App represents the application structure for interacting with GitLab API.
 type App struct {
 	gitlabClient *gitlab.Client
+	host         string         // Host of the GitLab instance, keys the cache and the snapshots
+	root         string         // Path of the instance on its host, e.g. "/gitlab", empty at the root
+	account      string         // Key of the token, keys the cache with the host
+	snapshots    *SnapshotStore // Set in offline mode, where issues are read from snapshots only
+	cache        *Cache         // Set by SetCache, nil when issues are always fetched from GitLab
+	refreshCache bool           // Discard the cached issues before syncing
 }
 
 // NewApp creates a new application instance with GitLab client.
-func NewApp(gitlabToken, gitlabURI string, timeout time.Duration) (*App, error) {
-	httpClient := &http.Client{
-		Timeout: timeout,
-	}
-
-	gitlabClient, err := gitlab.NewClient(
-		gitlabToken,
-		gitlab.WithBaseURL(gitlabURI),
-		gitlab.WithHTTPClient(httpClient),
-	)
+func NewApp(gitlabToken, gitlabURI string, opts ClientOptions) (*App, error) {
+	gitlabClient, err := NewGitlabClient(gitlabToken, gitlabURI, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+		return nil, err
 	}
+	baseURL := gitlabClient.BaseURL()
 	return &App{
 		gitlabClient: gitlabClient,
+		host:         baseURL.Host,
+		root:         strings.TrimSuffix(strings.TrimSuffix(baseURL.Path, "/"), "/api/v4"),
+		account:      accountKey(gitlabToken),
+	}, nil
+}
+
+// NewOfflineApp creates an application instance answering every request from the
+// snapshots of store taken from the GitLab instance at gitlabURI, without any network call.
+func NewOfflineApp(gitlabURI string, store *SnapshotStore) (*App, error) {
+	uri, err := url.Parse(gitlabURI)
+	if err != nil || uri.Host == "" {
+		return nil, fmt.Errorf("%w: %q", errInvalidGitlabURI, gitlabURI)
+	}
+	return &App{
+		host:      uri.Host,
+		root:      strings.TrimSuffix(uri.Path, "/"),
+		snapshots: store,
 	}, nil
 }
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// baseTime is the creation time of the first test issue.
+var baseTime = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// fakeGitLab serves the issues of project 1 like the GitLab issues API: filtered by
+// updated_after, most recently created first, by pages.
+type fakeGitLab struct {
+	mu       sync.Mutex
+	issues   []*gitlab.Issue // Issues of project 1
+	requests []url.Values    // Query of each issues request, in order
+	noTotals bool            // Omit X-Total-Pages, as GitLab does above 10,000 issues
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/v4/projects/1/issues" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	query := r.URL.Query()
+	f.mu.Lock()
+	f.requests = append(f.requests, query)
+	var matched []*gitlab.Issue
+	for _, issue := range f.issues {
+		if after, err := time.Parse(time.RFC3339, query.Get("updated_after")); err == nil &&
+			issue.UpdatedAt.Before(after) {
+			continue
+		}
+		copied := *issue
+		matched = append(matched, &copied)
+	}
+	f.mu.Unlock()
+	// Most recently created first, as GitLab
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	page, _ := strconv.Atoi(query.Get("page"))
+	page = max(page, 1)
+	totalPages := max((len(matched)+perPage-1)/perPage, 1)
+	start, end := min((page-1)*perPage, len(matched)), min(page*perPage, len(matched))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Page", strconv.Itoa(page))
+	w.Header().Set("X-Per-Page", strconv.Itoa(perPage))
+	if !f.noTotals {
+		w.Header().Set("X-Total-Pages", strconv.Itoa(totalPages))
+	}
+	if page < totalPages {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+	}
+	_ = json.NewEncoder(w).Encode(matched[start:end])
+}
+
+// add adds n issues to project 1, created an hour apart after the existing ones.
+func (f *fakeGitLab) add(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for range n {
+		id := int64(len(f.issues) + 1)
+		created := baseTime.Add(time.Duration(id) * time.Hour)
+		f.issues = append(f.issues, &gitlab.Issue{
+			ID: id, IID: id, ProjectID: 1, State: "opened", CreatedAt: &created, UpdatedAt: &created,
+		})
+	}
+}
+
+// update applies change to the issue with the given ID and marks it updated at.
+func (f *fakeGitLab) update(id int64, at time.Time, change func(*gitlab.Issue)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, issue := range f.issues {
+		if issue.ID == id {
+			change(issue)
+			issue.UpdatedAt = &at
+		}
+	}
+}
+
+// lastRequests returns the queries of the last n issues requests.
+func (f *fakeGitLab) lastRequests(n int) []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[max(len(f.requests)-n, 0):]
+}
+
+// requestedPages returns the page requested by each issues request, in order.
+func (f *fakeGitLab) requestedPages() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pages := make([]string, 0, len(f.requests))
+	for _, query := range f.requests {
+		pages = append(pages, query.Get("page"))
+	}
+	return pages
+}
+
+// newTestApp returns an application using the GitLab API served by handler.
+func newTestApp(t *testing.T, handler http.Handler) *App {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	app, err := NewApp("token", server.URL, ClientOptions{RetryWait: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+// issueIDs returns the IDs of the issues, in order.
+func issueIDs(issues []*gitlab.Issue) []int64 {
+	ids := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// matchesLocally reports whether the filters of g can be applied to issues already
+// downloaded. Full-text search, the started and upcoming milestones and the issues of
+// the whole instance depend on data only GitLab has.
+func (g *GetIssues) matchesLocally() bool {
+	milestone := strings.ToLower(g.Milestone)
+	return g.Search == "" && milestone != MilestoneStarted && milestone != MilestoneUpcoming && g.Scope == ""
+}
+
+// filterIssues returns the issues matching the filters of g, most recently created
+// first as in GitLab's default order.
+func filterIssues(issues []*gitlab.Issue, g *GetIssues) []*gitlab.Issue {
+	var matched []*gitlab.Issue
+	for _, issue := range issues {
+		if g.matches(issue) {
+			matched = append(matched, issue)
+		}
+	}
+	sortByCreation(matched)
+	return matched
+}
+
+// sortByCreation sorts issues in the default order of the GitLab API, most recently
+// created first.
+func sortByCreation(issues []*gitlab.Issue) {
+	slices.SortFunc(issues, func(a, b *gitlab.Issue) int {
+		return cmp.Or(compareTimes(b.CreatedAt, a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+}
+
+// emitIssues passes issues to fn by pages of the size requested from GitLab.
+func emitIssues(issues []*gitlab.Issue, fn IssuePageFunc) error {
+	for page := range slices.Chunk(issues, defaultPerPage) {
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches reports whether the issue matches the filters of g, with the semantics of
+// the GitLab issues API: date bounds are inclusive and labels match case-insensitively.
+func (g *GetIssues) matches(issue *gitlab.Issue) bool {
+	if g.State != "" && g.State != "all" && issue.State != g.State {
+		return false
+	}
+	if !inRange(issue.CreatedAt, g.FilterCreatedAtAfter, g.FilterCreatedAtBefore) ||
+		!inRange(issue.UpdatedAt, g.FilterUpdatedAtAfter, g.FilterUpdatedAtBefore) {
+		return false
+	}
+	if g.AssigneeUsername != "" && !slices.ContainsFunc(issue.Assignees, func(a *gitlab.IssueAssignee) bool {
+		return strings.EqualFold(a.Username, g.AssigneeUsername)
+	}) {
+		return false
+	}
+	if g.NoAssignee && (len(issue.Assignees) > 0 || issue.Assignee != nil) {
+		return false
+	}
+	if g.AuthorUsername != "" && (issue.Author == nil || !strings.EqualFold(issue.Author.Username, g.AuthorUsername)) {
+		return false
+	}
+	return g.matchesMilestone(issue) && g.matchesLabels(issue)
+}
+
+// matchesMilestone applies the milestone filter of g.
+func (g *GetIssues) matchesMilestone(issue *gitlab.Issue) bool {
+	switch strings.ToLower(g.Milestone) {
+	case "":
+		return true
+	case MilestoneNone:
+		return issue.Milestone == nil
+	case MilestoneAny:
+		return issue.Milestone != nil
+	default:
+		return issue.Milestone != nil && issue.Milestone.Title == g.Milestone
+	}
+}
+
+// matchesLabels applies the label filters of g. As in GitLab, a single label
+// "None" or "Any" matches issues without or with labels.
+func (g *GetIssues) matchesLabels(issue *gitlab.Issue) bool {
+	has := func(label string) bool {
+		return slices.ContainsFunc(issue.Labels, func(l string) bool { return strings.EqualFold(l, label) })
+	}
+	if len(g.Labels) == 1 && strings.EqualFold(g.Labels[0], "none") {
+		return len(issue.Labels) == 0
+	}
+	if len(g.Labels) == 1 && strings.EqualFold(g.Labels[0], "any") {
+		return len(issue.Labels) > 0
+	}
+	for _, label := range g.Labels {
+		if !has(label) {
+			return false
+		}
+	}
+	if slices.ContainsFunc(g.NotLabels, has) {
+		return false
+	}
+	return len(g.AnyLabels) == 0 || slices.ContainsFunc(g.AnyLabels, has)
+}
+
+// inRange reports whether t is within the inclusive bounds, a zero bound being unset.
+// A missing time only matches when both bounds are unset.
+func inRange(t *time.Time, after, before time.Time) bool {
+	if after.IsZero() && before.IsZero() {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	return (after.IsZero() || !t.Before(after)) && (before.IsZero() || !t.After(before))
+}
+
+// compareTimes compares two optional times, a missing time being the oldest.
+func compareTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	default:
+		return a.Compare(*b)
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestFilterIssues(t *testing.T) {
+	at := func(hours int) *time.Time {
+		t := baseTime.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	issues := []*gitlab.Issue{
+		{
+			ID: 1, State: "opened", CreatedAt: at(1), UpdatedAt: at(5), Labels: []string{"Bug", "backend"},
+			Assignees: []*gitlab.IssueAssignee{{Username: "alice"}}, Author: &gitlab.IssueAuthor{Username: "bob"},
+			Milestone: &gitlab.Milestone{Title: "v1.0"},
+		},
+		{ID: 2, State: "closed", CreatedAt: at(2), UpdatedAt: at(3), Labels: []string{"frontend"}},
+		{
+			ID: 3, State: "opened", CreatedAt: at(3), UpdatedAt: at(3),
+			Assignees: []*gitlab.IssueAssignee{{Username: "Bob"}}, Author: &gitlab.IssueAuthor{Username: "alice"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		query GetIssues
+		want  []int64
+	}{
+		{"no filter, most recently created first", GetIssues{}, []int64{3, 2, 1}},
+		{"state", GetIssues{State: "opened"}, []int64{3, 1}},
+		{"state all", GetIssues{State: "all"}, []int64{3, 2, 1}},
+		{"created bounds are inclusive", GetIssues{FilterCreatedAtAfter: *at(2), FilterCreatedAtBefore: *at(3)}, []int64{3, 2}},
+		{"updated after", GetIssues{FilterUpdatedAtAfter: *at(4)}, []int64{1}},
+		{"labels are case-insensitive", GetIssues{Labels: []string{"bug", "BACKEND"}}, []int64{1}},
+		{"label none", GetIssues{Labels: []string{"None"}}, []int64{3}},
+		{"label any", GetIssues{Labels: []string{"any"}}, []int64{2, 1}},
+		{"not labels", GetIssues{NotLabels: []string{"bug"}}, []int64{3, 2}},
+		{"any labels", GetIssues{AnyLabels: []string{"frontend", "backend"}}, []int64{2, 1}},
+		{"assignee is case-insensitive", GetIssues{AssigneeUsername: "bob"}, []int64{3}},
+		{"no assignee", GetIssues{NoAssignee: true}, []int64{2}},
+		{"author", GetIssues{AuthorUsername: "alice"}, []int64{3}},
+		{"milestone title", GetIssues{Milestone: "v1.0"}, []int64{1}},
+		{"milestone none", GetIssues{Milestone: "None"}, []int64{3, 2}},
+		{"milestone any", GetIssues{Milestone: MilestoneAny}, []int64{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issueIDs(filterIssues(issues, &tt.query)); !slices.Equal(got, tt.want) {
+				t.Errorf("filterIssues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesLocally(t *testing.T) {
+	tests := []struct {
+		query GetIssues
+		want  bool
+	}{
+		{GetIssues{State: "opened", Labels: []string{"bug"}, Milestone: "v1.0"}, true},
+		{GetIssues{Search: "crash"}, false},
+		{GetIssues{Milestone: "Started"}, false},
+		{GetIssues{Milestone: MilestoneUpcoming}, false},
+		{GetIssues{Scope: ScopeAssignedToMe}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.query.matchesLocally(); got != tt.want {
+			t.Errorf("matchesLocally() of %+v = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -1,15 +1,20 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
 // GetProjectPath retrieves the path with namespace for a project.
-func (a *App) GetProjectPath(projectID int64) (string, error) {
-	project, _, err := a.gitlabClient.Projects.GetProject(int(projectID), nil)
+func (a *App) GetProjectPath(ctx context.Context, projectID int64) (string, error) {
+	if a.snapshots != nil {
+		return a.snapshotPath(ScopeProject, projectID)
+	}
+	project, _, err := a.gitlabClient.Projects.GetProject(int(projectID), nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return "", fmt.Errorf("failed to get project %d: %w", projectID, err)
 	}
@@ -17,16 +22,28 @@ func (a *App) GetProjectPath(projectID int64) (string, error) {
 }
 
 // GetGroupPath retrieves the full path for a group.
-func (a *App) GetGroupPath(groupID int64) (string, error) {
-	group, _, err := a.gitlabClient.Groups.GetGroup(int(groupID), nil, nil)
+func (a *App) GetGroupPath(ctx context.Context, groupID int64) (string, error) {
+	if a.snapshots != nil {
+		return a.snapshotPath(ScopeGroup, groupID)
+	}
+	group, _, err := a.gitlabClient.Groups.GetGroup(int(groupID), nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return "", fmt.Errorf("failed to get group %d: %w", groupID, err)
 	}
 	return group.FullPath, nil
 }
 
+// maxConcurrentRequests bounds the number of API requests made in parallel.
+const maxConcurrentRequests = 8
+
 // GetProjectPathsForIssues builds a map of projectID -> path for all unique projects in issues.
-func (a *App) GetProjectPathsForIssues(issues []*gitlab.Issue) (map[int64]string, error) {
+// Paths are fetched concurrently, at most maxConcurrentRequests at a time.
+// Projects not yet resolved when ctx is cancelled are left out and ctx's error is returned.
+func (a *App) GetProjectPathsForIssues(ctx context.Context, issues []*gitlab.Issue) (map[int64]string, error) {
+	if a.snapshots != nil {
+		return a.snapshotProjectPaths(issues), nil
+	}
+
 	// Collect unique project IDs
 	projectIDs := make(map[int64]bool)
 	for _, issue := range issues {
@@ -34,17 +51,77 @@ func (a *App) GetProjectPathsForIssues(issues []*gitlab.Issue) (map[int64]string
 	}
 
 	// Fetch project paths
-	projectPaths := make(map[int64]string)
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		slots        = make(chan struct{}, maxConcurrentRequests)
+		projectPaths = make(map[int64]string, len(projectIDs))
+	)
 	for projectID := range projectIDs {
-		path, err := a.GetProjectPath(projectID)
+		wg.Go(func() {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+
+			path, err := a.GetProjectPath(ctx, projectID)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				// Log warning but continue with other projects
+				logrus.Warnf("Failed to fetch path for project %d: %v", projectID, err)
+				path = fmt.Sprintf("ID:%d", projectID)
+			}
+			mu.Lock()
+			projectPaths[projectID] = path
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return projectPaths, fmt.Errorf("project path resolution interrupted: %w", err)
+	}
+	return projectPaths, nil
+}
+
+// GetGroupProjectPaths builds a map of projectID -> path for all projects of a group,
+// including the projects of its subgroups, with one paginated listing instead of one
+// request per project.
+func (a *App) GetGroupProjectPaths(ctx context.Context, groupID int64) (map[int64]string, error) {
+	if a.snapshots != nil {
+		snapshot, err := a.snapshots.Load(a.host, ScopeGroup, groupID)
 		if err != nil {
-			// Log warning but continue with other projects
-			logrus.Warnf("Failed to fetch path for project %d: %v", projectID, err)
-			projectPaths[projectID] = fmt.Sprintf("ID:%d", projectID)
-			continue
+			return nil, err
 		}
-		projectPaths[projectID] = path
+		return snapshot.ProjectPaths, nil
 	}
 
+	listOptions := gitlab.ListGroupProjectsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: defaultPerPage,
+			Page:    1,
+		},
+		IncludeSubGroups: gitlab.Ptr(true),
+		Simple:           gitlab.Ptr(true),
+	}
+
+	projectPaths := make(map[int64]string)
+	for {
+		projects, resp, err := a.gitlabClient.Groups.ListGroupProjects(groupID, &listOptions, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects of group %d: %w", groupID, err)
+		}
+		for _, project := range projects {
+			projectPaths[project.ID] = project.PathWithNamespace
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		listOptions.Page++
+	}
 	return projectPaths, nil
 }
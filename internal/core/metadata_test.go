@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestGetProjectPathsForIssues(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	app := newTestApp(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v4/projects/")
+		mu.Lock()
+		requests[id]++
+		mu.Unlock()
+		if id == "3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"id": %s, "path_with_namespace": "group/project-%s"}`, id, id)
+	}))
+
+	var issues []*gitlab.Issue
+	for _, projectID := range []int64{1, 2, 1, 3, 2, 1} {
+		issues = append(issues, &gitlab.Issue{ProjectID: projectID})
+	}
+	paths, err := app.GetProjectPathsForIssues(t.Context(), issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int64]string{1: "group/project-1", 2: "group/project-2", 3: "ID:3"}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("GetProjectPathsForIssues() = %v, want %v", paths, want)
+	}
+	if want := map[string]int{"1": 1, "2": 1, "3": 1}; fmt.Sprint(requests) != fmt.Sprint(want) {
+		t.Errorf("requests by project = %v, want a single request per project", requests)
+	}
+}
+
+func TestGetProjectPathsForIssuesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	app := newTestApp(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v4/projects/")
+		if id == "2" {
+			// Project 2 is still being resolved when the run is interrupted
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"id": %s, "path_with_namespace": "group/project-%s"}`, id, id)
+	}))
+
+	issues := []*gitlab.Issue{{ProjectID: 1}, {ProjectID: 2}, {ProjectID: 3}}
+	paths, err := app.GetProjectPathsForIssues(ctx, issues)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetProjectPathsForIssues() error = %v, want context.Canceled", err)
+	}
+	if _, ok := paths[2]; ok {
+		t.Errorf("GetProjectPathsForIssues() = %v, want project 2 left out", paths)
+	}
+	for id, path := range paths {
+		if path != "group/project-"+strconv.FormatInt(id, 10) {
+			t.Errorf("path of project %d = %q, want its path or nothing", id, path)
+		}
+	}
+
+	// Nothing is resolved once the context is cancelled
+	paths, err = app.GetProjectPathsForIssues(ctx, issues)
+	if !errors.Is(err, context.Canceled) || len(paths) != 0 {
+		t.Errorf("GetProjectPathsForIssues() with a cancelled context = %v, %v, want no path", paths, err)
+	}
+}
+
+func TestGetGroupProjectPaths(t *testing.T) {
+	// Group 5 has 250 projects, in the group and in its subgroups
+	var projects []map[string]any
+	for id := 1; id <= 250; id++ {
+		path := fmt.Sprintf("group/project-%d", id)
+		if id%2 == 0 {
+			path = fmt.Sprintf("group/sub-%d/project-%d", id%3, id)
+		}
+		projects = append(projects, map[string]any{"id": id, "path_with_namespace": path})
+	}
+	var pages []string
+	app := newTestApp(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/v4/groups/5/projects" || query.Get("include_subgroups") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		pages = append(pages, query.Get("page"))
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		start, end := min((page-1)*perPage, len(projects)), min(page*perPage, len(projects))
+		if end < len(projects) {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(projects[start:end])
+	}))
+
+	paths, err := app.GetGroupProjectPaths(t.Context(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 250 || paths[1] != "group/project-1" || paths[4] != "group/sub-1/project-4" {
+		t.Errorf("GetGroupProjectPaths() = %d paths, 1: %q, 4: %q, want 250 with the subgroup paths",
+			len(paths), paths[1], paths[4])
+	}
+	if fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("requested pages %v, want 1 to 3", pages)
+	}
+
+	if _, err := app.GetGroupProjectPaths(t.Context(), 6); err == nil {
+		t.Error("GetGroupProjectPaths() of an unknown group should fail")
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// listIssuesFunc fetches one page of issues. The request options select the page and
+// override the context of the request; without options the first page is returned.
+type listIssuesFunc func(options ...gitlab.RequestOptionFunc) ([]*gitlab.Issue, *gitlab.Response, error)
+
+// paginateIssues passes every page of issues returned by list to fn, in order.
+//
+// When the first response reports the total number of pages, the remaining pages are
+// fetched with up to concurrency requests in flight. GitLab omits the totals for large
+// result sets (above 10,000 issues); the pages are then fetched ahead by number, up to
+// concurrency at a time, until a page has fewer issues than the page size, which costs
+// up to concurrency-1 requests past the last page. Keyset pagination is not used as a
+// fallback: the GitLab issues endpoints only support offset pagination. Responses linking
+// to the next page without a page number are still followed one page at a time.
+// Errors returned by fn are passed through unchanged; list errors are described with source.
+func paginateIssues(ctx context.Context, source string, list listIssuesFunc, concurrency int, fn IssuePageFunc) error {
+	issues, resp, err := list()
+	if err != nil {
+		return fmt.Errorf("failed to list %s issues: %w", source, err)
+	}
+	if err := fn(issues); err != nil {
+		return err
+	}
+
+	if concurrency > 1 {
+		switch {
+		case resp.TotalPages > 1:
+			pages := pageRange{first: resp.CurrentPage + 1, last: resp.TotalPages}
+			return fetchPagesConcurrently(ctx, source, list, pages, concurrency, fn)
+		case resp.TotalPages == 0 && resp.NextLink == "" && resp.NextPage != 0:
+			pages := pageRange{first: resp.NextPage, perPage: max(int(resp.ItemsPerPage), len(issues))}
+			return fetchPagesConcurrently(ctx, source, list, pages, concurrency, fn)
+		}
+	}
+
+	for {
+		next, ok := gitlab.WithNext(resp)
+		if !ok {
+			return nil
+		}
+		issues, resp, err = list(next)
+		if err != nil {
+			return fmt.Errorf("failed to list %s issues: %w", source, err)
+		}
+		if err := fn(issues); err != nil {
+			return err
+		}
+	}
+}
+
+// pageRange selects the pages to fetch: first to last or, when last is 0 because the
+// total is unknown, up to the first page having fewer than perPage issues.
+type pageRange struct {
+	first, last int64
+	perPage     int
+}
+
+// pageResult is the outcome of fetching one page.
+type pageResult struct {
+	issues []*gitlab.Issue
+	err    error
+}
+
+// fetchPagesConcurrently fetches the pages of the range in order and passes them to fn
+// in page order. A page slot is released only once fn has consumed the page, so at most
+// concurrency pages are in flight or waiting in memory at any time. When the total is
+// unknown, up to concurrency-1 pages past the last one are requested and discarded.
+// The requests still in flight are cancelled when it returns, and no page is requested
+// after an error.
+func fetchPagesConcurrently(
+	ctx context.Context,
+	source string,
+	list listIssuesFunc,
+	pages pageRange,
+	concurrency int,
+	fn IssuePageFunc,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	slots := make(chan struct{}, concurrency)
+
+	// Pages are queued in order; the slots bound the queue to concurrency pages
+	pending := make(chan chan pageResult, concurrency)
+	go func() {
+		defer close(pending)
+		for page := pages.first; pages.last == 0 || page <= pages.last; page++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			// Both cases may be ready at once, the slot must not launch a page once done
+			if ctx.Err() != nil {
+				return
+			}
+			result := make(chan pageResult, 1)
+			go func() {
+				issues, _, err := list(gitlab.WithContext(ctx), gitlab.WithOffsetPaginationParameters(page))
+				if err != nil {
+					err = fmt.Errorf("failed to list %s issues (page %d): %w", source, page, err)
+				}
+				result <- pageResult{issues: issues, err: err}
+			}()
+			pending <- result
+		}
+	}()
+
+	for result := range pending {
+		r := <-result
+		if r.err != nil {
+			return r.err
+		}
+		if len(r.issues) > 0 {
+			if err := fn(r.issues); err != nil {
+				return err
+			}
+		}
+		<-slots
+		if pages.last == 0 && len(r.issues) < pages.perPage {
+			return nil
+		}
+	}
+	return nil
+}
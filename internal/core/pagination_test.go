@@ -0,0 +1,136 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestStreamIssuesPageOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		issues   int
+		noTotals bool
+	}{
+		{name: "with totals", issues: 250},
+		{name: "without totals", issues: 250, noTotals: true},
+		{name: "without totals, full last page", issues: 200, noTotals: true},
+		{name: "single page", issues: 40, noTotals: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGitLab{noTotals: tt.noTotals}
+			fake.add(tt.issues)
+			app := newTestApp(t, fake)
+
+			var got []int64
+			pages := 0
+			err := app.StreamIssues(t.Context(), func(issues []*gitlab.Issue) error {
+				if len(issues) == 0 {
+					t.Error("StreamIssues() passed an empty page")
+				}
+				pages++
+				got = append(got, issueIDs(issues)...)
+				return nil
+			}, WithProjectID(1), WithConcurrency(DefaultConcurrency))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := make([]int64, 0, tt.issues)
+			for id := int64(tt.issues); id > 0; id-- {
+				want = append(want, id)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("StreamIssues() passed issues %v, want %d issues newest first", got, tt.issues)
+			}
+			if wantPages := (tt.issues + defaultPerPage - 1) / defaultPerPage; pages != wantPages {
+				t.Errorf("StreamIssues() passed %d pages, want %d", pages, wantPages)
+			}
+		})
+	}
+}
+
+func TestStreamIssuesFetchesAheadWithoutTotals(t *testing.T) {
+	fake := &fakeGitLab{noTotals: true}
+	fake.add(5 * defaultPerPage)
+	var inFlight, maxInFlight atomic.Int32
+	app := newTestApp(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for current := maxInFlight.Load(); n > current && !maxInFlight.CompareAndSwap(current, n); {
+			current = maxInFlight.Load()
+		}
+		time.Sleep(20 * time.Millisecond)
+		fake.ServeHTTP(w, r)
+	}))
+
+	err := app.StreamIssues(t.Context(), func(_ []*gitlab.Issue) error { return nil },
+		WithProjectID(1), WithConcurrency(DefaultConcurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := maxInFlight.Load(); got < 2 || got > DefaultConcurrency {
+		t.Errorf("%d requests in flight at most, want between 2 and %d", got, DefaultConcurrency)
+	}
+
+	// The pages past the first are requested by number, at most concurrency-1 past the last one
+	pages := fake.requestedPages()
+	if len(pages) < 6 || len(pages) > 6+DefaultConcurrency-1 {
+		t.Errorf("requested pages %v, want pages 1 to 6 and at most %d more", pages, DefaultConcurrency-1)
+	}
+	for _, page := range []string{"2", "3", "4", "5", "6"} {
+		if !slices.Contains(pages, page) {
+			t.Errorf("page %s was not requested, requested %v", page, pages)
+		}
+	}
+}
+
+func TestStreamIssuesCancelsPagesOnError(t *testing.T) {
+	fake := &fakeGitLab{}
+	fake.add(8 * defaultPerPage)
+	var cancelled atomic.Int32
+	app := newTestApp(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Pages past the second never complete unless their request is cancelled
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" && page != "2" {
+			fake.ServeHTTP(httptest.NewRecorder(), r)
+			select {
+			case <-r.Context().Done():
+				cancelled.Add(1)
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+
+	errStop := errors.New("stop")
+	start := time.Now()
+	pages := 0
+	err := app.StreamIssues(t.Context(), func(_ []*gitlab.Issue) error {
+		if pages++; pages == 2 {
+			return errStop
+		}
+		return nil
+	}, WithProjectID(1), WithConcurrency(DefaultConcurrency))
+	if !errors.Is(err, errStop) || time.Since(start) > 2*time.Second {
+		t.Fatalf("StreamIssues() = %v after %v, want errStop without waiting for the pages in flight",
+			err, time.Since(start))
+	}
+
+	// No page is requested once fn failed, and the requests in flight are cancelled
+	requested := len(fake.requestedPages())
+	time.Sleep(100 * time.Millisecond)
+	if got := len(fake.requestedPages()); got != requested {
+		t.Errorf("%d pages requested after StreamIssues() returned", got-requested)
+	}
+	if got := cancelled.Load(); int(got) != requested-2 {
+		t.Errorf("%d of the %d requests in flight cancelled, want all", got, requested-2)
+	}
+}
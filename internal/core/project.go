@@ -1,33 +1,73 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/sirupsen/logrus"
+
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
 // Static error definitions.
 var (
-	errMissingIDs     = errors.New("projectID or groupID must be set")
-	errConflictingIDs = errors.New("projectID and groupID cannot be set at the same time")
+	errMissingIDs       = errors.New("project, group or scope must be set")
+	errConflictingIDs   = errors.New("project and group cannot be set at the same time")
+	errConflictingScope = errors.New("scope cannot be set with a project or group")
+	errInvalidScope     = errors.New("invalid scope")
+	errConflictAssignee = errors.New("assignee username and no assignee cannot be set at the same time")
+)
+
+// Special milestone filter values. Any other value is matched against the milestone title.
+const (
+	MilestoneNone     = "none"     // Issues without a milestone
+	MilestoneAny      = "any"      // Issues with any milestone
+	MilestoneStarted  = "started"  // Issues in a milestone that has started and is not expired
+	MilestoneUpcoming = "upcoming" // Issues in the next milestone to start
+)
+
+// Scopes of the issues listed across the GitLab instance, see WithScope.
+const (
+	ScopeAssignedToMe = "assigned_to_me" // Issues assigned to the user of the token
+	ScopeCreatedByMe  = "created_by_me"  // Issues created by the user of the token
+	ScopeAll          = "all"            // Issues the user of the token can see
 )
 
+// Scopes lists the valid scopes, in the order they are documented.
+var Scopes = []string{ScopeAssignedToMe, ScopeCreatedByMe, ScopeAll}
+
 // Default pagination value for GitLab API requests.
 const defaultPerPage = 100
 
+// DefaultConcurrency is the default number of issue pages fetched in parallel.
+const DefaultConcurrency = 4
+
 // GetIssues contains parameters for retrieving issues from GitLab.
 type GetIssues struct {
 	ProjectID             int64
+	ProjectPath           string // Path or web URL of the project, resolved to ProjectID
 	GroupID               int64
+	GroupPath             string // Path or web URL of the group, resolved to GroupID
+	Scope                 string // Lists the issues of the whole instance in this scope
 	State                 string
 	FilterCreatedAtAfter  time.Time
 	FilterCreatedAtBefore time.Time
 	FilterUpdatedAtAfter  time.Time
 	FilterUpdatedAtBefore time.Time
 	AssigneeUsername      string
+	NoAssignee            bool
+	AuthorUsername        string
+	Milestone             string
 	Labels                []string
+	NotLabels             []string
+	AnyLabels             []string
+	Search                string
+	Concurrency           int
 }
 
 // GetIssuesOption is a functional option for configuring the GetIssues struct.
@@ -82,6 +122,31 @@ func WithGroupID(groupID int64) GetIssuesOption {
 	}
 }
 
+// WithProjectPath sets the project for retrieving issues by its path, e.g.
+// "group/subgroup/project", or by the URL of one of its web pages.
+func WithProjectPath(projectPath string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.ProjectPath = projectPath
+	}
+}
+
+// WithGroupPath sets the group for retrieving issues by its full path, e.g.
+// "group/subgroup", or by the URL of one of its web pages.
+func WithGroupPath(groupPath string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.GroupPath = groupPath
+	}
+}
+
+// WithScope lists the issues of the whole GitLab instance in scope, one of
+// ScopeAssignedToMe, ScopeCreatedByMe and ScopeAll, instead of those of a project or
+// group. Such queries are always sent to GitLab, bypassing the cache and snapshots.
+func WithScope(scope string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.Scope = scope
+	}
+}
+
 // WithFilterCreatedAt filters issues by creation date range.
 func WithFilterCreatedAt(filterCreatedAtAfter time.Time, filterCreatedAtBefore time.Time) GetIssuesOption {
 	return func(g *GetIssues) {
@@ -126,27 +191,158 @@ func WithLabels(labels []string) GetIssuesOption {
 	}
 }
 
-// GetIssues retrieves GitLab issues based on the provided options.
-func (a *App) GetIssues(opts ...GetIssuesOption) ([]*gitlab.Issue, error) {
-	g := &GetIssues{}
+// WithNoAssignee filters issues that are not assigned to anyone.
+func WithNoAssignee() GetIssuesOption {
+	return func(g *GetIssues) {
+		g.NoAssignee = true
+	}
+}
+
+// WithAuthorUsername filters issues by author username.
+func WithAuthorUsername(authorUsername string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.AuthorUsername = authorUsername
+	}
+}
+
+// WithMilestone filters issues by milestone title, or by one of the special values
+// MilestoneNone, MilestoneAny, MilestoneStarted and MilestoneUpcoming (case-insensitive).
+func WithMilestone(milestone string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.Milestone = milestone
+	}
+}
+
+// WithNotLabels filters out issues that have any of the specified labels.
+func WithNotLabels(labels []string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.NotLabels = labels
+	}
+}
+
+// WithAnyLabels filters issues that have at least one of the specified labels.
+// GitLab has no OR filter for labels, so one query is made per label and the
+// results are merged: an issue matching several labels is returned once.
+func WithAnyLabels(labels []string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.AnyLabels = labels
+	}
+}
+
+// WithSearch filters issues whose title or description contains the text.
+func WithSearch(search string) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.Search = search
+	}
+}
+
+// WithConcurrency sets the maximum number of pages fetched in parallel.
+// A value of 1 fetches pages one at a time.
+func WithConcurrency(concurrency int) GetIssuesOption {
+	return func(g *GetIssues) {
+		g.Concurrency = concurrency
+	}
+}
+
+// IssuePageFunc is called with every page of issues returned by the GitLab API.
+// Returning an error stops the pagination and is returned to the caller.
+type IssuePageFunc func(issues []*gitlab.Issue) error
+
+// GetIssues retrieves GitLab issues based on the provided options, most recently
+// created first, from the cache or not. When an error occurs, for instance because ctx
+// is cancelled, the issues fetched until then are returned along with the error.
+func (a *App) GetIssues(ctx context.Context, opts ...GetIssuesOption) ([]*gitlab.Issue, error) {
+	var allIssues []*gitlab.Issue
+	err := a.StreamIssues(ctx, func(issues []*gitlab.Issue) error {
+		allIssues = append(allIssues, issues...)
+		return nil
+	}, opts...)
+	if a.cache != nil {
+		// The cache streams the downloaded issues before the cached ones, the issues are
+		// put back in the order of the API so that the report does not depend on the cache
+		sortByCreation(allIssues)
+	}
+	return allIssues, err
+}
+
+// StreamIssues retrieves GitLab issues based on the provided options and passes
+// each page to fn as soon as it is received, without keeping previous pages in memory.
+// Pagination stops as soon as ctx is cancelled. When a cache is set, the pages are
+// made of the issues matching the options downloaded by the sync of the cache, as
+// they arrive, then of the cached ones: the issues are not in the order of the API.
+// In offline mode, the pages are made of the issues of the snapshot matching the options.
+func (a *App) StreamIssues(ctx context.Context, fn IssuePageFunc, opts ...GetIssuesOption) error {
+	g := &GetIssues{Concurrency: DefaultConcurrency}
 	for _, opt := range opts {
 		opt(g)
 	}
 	if err := g.validate(); err != nil {
-		return nil, err
+		return err
+	}
+	if err := a.resolvePaths(ctx, g); err != nil {
+		return err
 	}
+	if a.snapshots != nil {
+		return a.streamSnapshotIssues(g, fn)
+	}
+	if a.cache != nil {
+		if g.matchesLocally() {
+			return a.streamCachedIssues(ctx, g, fn)
+		}
+		logrus.Debugf("Search, started or upcoming milestones and scopes bypass the cache")
+	}
+	if len(g.AnyLabels) > 0 {
+		return a.streamIssuesWithAnyLabel(ctx, g, fn)
+	}
+	return a.streamIssues(ctx, g, fn)
+}
+
+// streamIssues lists the issues of the project, group or scope selected by g.
+func (a *App) streamIssues(ctx context.Context, g *GetIssues, fn IssuePageFunc) error {
 	if g.ProjectID != 0 {
-		return a.getIssuesOfProject(g)
+		return a.getIssuesOfProject(ctx, g, fn)
 	}
 	if g.GroupID != 0 {
-		return a.getIssuesOfGroup(g)
+		return a.getIssuesOfGroup(ctx, g, fn)
 	}
-	return nil, fmt.Errorf("cannot get issues: %w", errMissingIDs)
+	if g.Scope != "" {
+		return a.getIssuesOfInstance(ctx, g, fn)
+	}
+	return fmt.Errorf("cannot get issues: %w", errMissingIDs)
+}
+
+// streamIssuesWithAnyLabel runs one query per label of g.AnyLabels, in addition to
+// the other filters, and skips issues already returned by a previous query.
+func (a *App) streamIssuesWithAnyLabel(ctx context.Context, g *GetIssues, fn IssuePageFunc) error {
+	seen := make(map[int64]bool)
+	for _, label := range g.AnyLabels {
+		query := *g
+		query.AnyLabels = nil
+		query.Labels = append(append([]string(nil), g.Labels...), label)
+
+		err := a.streamIssues(ctx, &query, func(issues []*gitlab.Issue) error {
+			page := make([]*gitlab.Issue, 0, len(issues))
+			for _, issue := range issues {
+				if !seen[issue.ID] {
+					seen[issue.ID] = true
+					page = append(page, issue)
+				}
+			}
+			if len(page) == 0 {
+				return nil
+			}
+			return fn(page)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // applyIssueFilters applies common filter settings to issue list options.
 func applyIssueFilters(g *GetIssues, listOptions any) {
-	// Use type switches to handle both project and group issue options
+	// Use type switches to handle project, group and instance issue options
 	switch opts := listOptions.(type) {
 	case *gitlab.ListProjectIssuesOptions:
 		applyCommonFilters(
@@ -157,14 +353,16 @@ func applyIssueFilters(g *GetIssues, listOptions any) {
 			&opts.UpdatedAfter,
 			&opts.UpdatedBefore,
 		)
-		// Add assignee username filter
-		if g.AssigneeUsername != "" {
-			opts.AssigneeUsername = &g.AssigneeUsername
-		}
-		if len(g.Labels) > 0 {
-			labels := gitlab.LabelOptions(g.Labels)
-			opts.Labels = &labels
-		}
+		applyMatchFilters(
+			g,
+			&opts.AssigneeUsername,
+			&opts.AssigneeID,
+			&opts.AuthorUsername,
+			&opts.Milestone,
+			&opts.Search,
+			&opts.Labels,
+			&opts.NotLabels,
+		)
 	case *gitlab.ListGroupIssuesOptions:
 		applyCommonFilters(
 			g,
@@ -174,14 +372,36 @@ func applyIssueFilters(g *GetIssues, listOptions any) {
 			&opts.UpdatedAfter,
 			&opts.UpdatedBefore,
 		)
-		// Add assignee username filter
-		if g.AssigneeUsername != "" {
-			opts.AssigneeUsername = &g.AssigneeUsername
-		}
-		if len(g.Labels) > 0 {
-			labels := gitlab.LabelOptions(g.Labels)
-			opts.Labels = &labels
-		}
+		applyMatchFilters(
+			g,
+			&opts.AssigneeUsername,
+			&opts.AssigneeID,
+			&opts.AuthorUsername,
+			&opts.Milestone,
+			&opts.Search,
+			&opts.Labels,
+			&opts.NotLabels,
+		)
+	case *gitlab.ListIssuesOptions:
+		setStringFilter(&opts.Scope, g.Scope)
+		applyCommonFilters(
+			g,
+			&opts.State,
+			&opts.CreatedAfter,
+			&opts.CreatedBefore,
+			&opts.UpdatedAfter,
+			&opts.UpdatedBefore,
+		)
+		applyMatchFilters(
+			g,
+			&opts.AssigneeUsername,
+			&opts.AssigneeID,
+			&opts.AuthorUsername,
+			&opts.Milestone,
+			&opts.Search,
+			&opts.Labels,
+			&opts.NotLabels,
+		)
 	}
 }
 
@@ -199,6 +419,72 @@ func applyCommonFilters(
 	setTimeFilter(updatedBefore, g.FilterUpdatedAtBefore)
 }
 
+// applyMatchFilters sets the assignee, author, milestone, search and label filters
+// for different GitLab API option types.
+func applyMatchFilters(
+	g *GetIssues,
+	assigneeUsername **string,
+	assigneeID **gitlab.AssigneeIDValue,
+	authorUsername, milestone, search **string,
+	labels, notLabels **gitlab.LabelOptions,
+) {
+	setStringFilter(assigneeUsername, g.AssigneeUsername)
+	if g.NoAssignee {
+		*assigneeID = gitlab.AssigneeID(gitlab.UserIDNone)
+	}
+	setStringFilter(authorUsername, g.AuthorUsername)
+	setStringFilter(search, g.Search)
+	setLabelsFilter(labels, g.Labels)
+	setLabelsFilter(notLabels, g.NotLabels)
+
+	// Started and upcoming are only available through milestone_id, see milestoneRequestOptions
+	switch strings.ToLower(g.Milestone) {
+	case "", MilestoneStarted, MilestoneUpcoming:
+	case MilestoneNone:
+		setStringFilter(milestone, "None")
+	case MilestoneAny:
+		setStringFilter(milestone, "Any")
+	default:
+		setStringFilter(milestone, g.Milestone)
+	}
+}
+
+// requestOptions returns the options of a list request: the context, the
+// milestone_id parameter when needed, and the page selection.
+func (g *GetIssues) requestOptions(ctx context.Context, page ...gitlab.RequestOptionFunc) []gitlab.RequestOptionFunc {
+	options := []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)}
+	options = append(options, g.milestoneRequestOptions()...)
+	return append(options, page...)
+}
+
+// milestoneRequestOptions returns the request options adding the milestone_id
+// parameter, which the list options of the GitLab client do not expose.
+func (g *GetIssues) milestoneRequestOptions() []gitlab.RequestOptionFunc {
+	var value string
+	switch strings.ToLower(g.Milestone) {
+	case MilestoneStarted:
+		value = "Started"
+	case MilestoneUpcoming:
+		value = "Upcoming"
+	default:
+		return nil
+	}
+	return []gitlab.RequestOptionFunc{func(req *retryablehttp.Request) error {
+		q := req.URL.Query()
+		q.Set("milestone_id", value)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}}
+}
+
+// setLabelsFilter sets a labels filter if the list is not empty.
+func setLabelsFilter(target **gitlab.LabelOptions, values []string) {
+	if target != nil && len(values) > 0 {
+		labels := gitlab.LabelOptions(values)
+		*target = &labels
+	}
+}
+
 // setStringFilter safely sets a string filter if the pointer is not nil and the value is not empty.
 func setStringFilter(target **string, value string) {
 	if target != nil && value != "" {
@@ -213,8 +499,7 @@ func setTimeFilter(target **time.Time, value time.Time) {
 	}
 }
 
-func (a *App) getIssuesOfProject(g *GetIssues) ([]*gitlab.Issue, error) {
-	var allIssues []*gitlab.Issue
+func (a *App) getIssuesOfProject(ctx context.Context, g *GetIssues, fn IssuePageFunc) error {
 	listOptions := gitlab.ListProjectIssuesOptions{
 		ListOptions: gitlab.ListOptions{
 			PerPage: defaultPerPage,
@@ -225,22 +510,13 @@ func (a *App) getIssuesOfProject(g *GetIssues) ([]*gitlab.Issue, error) {
 	// Apply filters
 	applyIssueFilters(g, &listOptions)
 
-	for {
-		issues, resp, err := a.gitlabClient.Issues.ListProjectIssues(g.ProjectID, &listOptions)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list project issues: %w", err)
-		}
-		allIssues = append(allIssues, issues...)
-		if resp.NextPage == 0 {
-			break
-		}
-		listOptions.Page++
+	list := func(options ...gitlab.RequestOptionFunc) ([]*gitlab.Issue, *gitlab.Response, error) {
+		return a.gitlabClient.Issues.ListProjectIssues(g.ProjectID, &listOptions, g.requestOptions(ctx, options...)...)
 	}
-	return allIssues, nil
+	return paginateIssues(ctx, "project", list, g.Concurrency, fn)
 }
 
-func (a *App) getIssuesOfGroup(g *GetIssues) ([]*gitlab.Issue, error) {
-	var allIssues []*gitlab.Issue
+func (a *App) getIssuesOfGroup(ctx context.Context, g *GetIssues, fn IssuePageFunc) error {
 	listOptions := gitlab.ListGroupIssuesOptions{
 		ListOptions: gitlab.ListOptions{
 			PerPage: defaultPerPage,
@@ -251,26 +527,47 @@ func (a *App) getIssuesOfGroup(g *GetIssues) ([]*gitlab.Issue, error) {
 	// Apply filters
 	applyIssueFilters(g, &listOptions)
 
-	for {
-		issues, resp, err := a.gitlabClient.Issues.ListGroupIssues(g.GroupID, &listOptions)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list group issues: %w", err)
-		}
-		allIssues = append(allIssues, issues...)
-		if resp.NextPage == 0 {
-			break
-		}
-		listOptions.Page++
+	list := func(options ...gitlab.RequestOptionFunc) ([]*gitlab.Issue, *gitlab.Response, error) {
+		return a.gitlabClient.Issues.ListGroupIssues(g.GroupID, &listOptions, g.requestOptions(ctx, options...)...)
 	}
-	return allIssues, nil
+	return paginateIssues(ctx, "group", list, g.Concurrency, fn)
+}
+
+// getIssuesOfInstance lists the issues of the whole instance in the scope of g, through
+// the /issues endpoint.
+func (a *App) getIssuesOfInstance(ctx context.Context, g *GetIssues, fn IssuePageFunc) error {
+	listOptions := gitlab.ListIssuesOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: defaultPerPage,
+			Page:    1,
+		},
+	}
+
+	// Apply filters
+	applyIssueFilters(g, &listOptions)
+
+	list := func(options ...gitlab.RequestOptionFunc) ([]*gitlab.Issue, *gitlab.Response, error) {
+		return a.gitlabClient.Issues.ListIssues(&listOptions, g.requestOptions(ctx, options...)...)
+	}
+	return paginateIssues(ctx, "instance", list, g.Concurrency, fn)
 }
 
 func (g *GetIssues) validate() error {
-	if g.ProjectID == 0 && g.GroupID == 0 {
+	hasProject := g.ProjectID != 0 || g.ProjectPath != ""
+	hasGroup := g.GroupID != 0 || g.GroupPath != ""
+	switch {
+	case hasProject && hasGroup:
+		return fmt.Errorf("validation failed: %w", errConflictingIDs)
+	case g.Scope != "" && (hasProject || hasGroup):
+		return fmt.Errorf("validation failed: %w", errConflictingScope)
+	case g.Scope != "" && !slices.Contains(Scopes, g.Scope):
+		return fmt.Errorf("validation failed: %w %q, valid scopes: %s",
+			errInvalidScope, g.Scope, strings.Join(Scopes, ", "))
+	case !hasProject && !hasGroup && g.Scope == "":
 		return fmt.Errorf("validation failed: %w", errMissingIDs)
 	}
-	if g.ProjectID != 0 && g.GroupID != 0 {
-		return fmt.Errorf("validation failed: %w", errConflictingIDs)
+	if g.AssigneeUsername != "" && g.NoAssignee {
+		return fmt.Errorf("validation failed: %w", errConflictAssignee)
 	}
 	return nil
 }
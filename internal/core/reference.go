@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+var (
+	errEmptyReference   = errors.New("empty project or group reference")
+	errForeignURL       = errors.New("URL is not on the GitLab instance")
+	errInvalidReference = errors.New("invalid project or group reference")
+)
+
+// ParseReference parses a project or group given by numeric ID, by full path such as
+// "group/subgroup/project", or by the URL of one of its web pages, e.g.
+// https://gitlab.example.com/group/project/-/issues. It returns either the ID or the
+// path. URLs must be on the GitLab instance of the application.
+func (a *App) ParseReference(ref string) (id int64, path string, err error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return 0, "", errEmptyReference
+	}
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		if id <= 0 {
+			return 0, "", fmt.Errorf("%w: %q", errInvalidReference, ref)
+		}
+		return id, "", nil
+	}
+
+	path = ref
+	if strings.Contains(ref, "://") {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return 0, "", fmt.Errorf("%w: %q: %w", errInvalidReference, ref, err)
+		}
+		if !strings.EqualFold(u.Host, a.host) {
+			return 0, "", fmt.Errorf("%w %s: %q", errForeignURL, a.host, ref)
+		}
+		path = strings.TrimPrefix(u.Path, a.root)
+		// Web pages of a project or group are under /-/, old group pages under /groups/
+		path, _, _ = strings.Cut(path, "/-/")
+		path = strings.TrimPrefix(strings.Trim(path, "/"), "groups/")
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if path == "" || strings.Contains(path, "//") {
+		return 0, "", fmt.Errorf("%w: %q", errInvalidReference, ref)
+	}
+	return 0, path, nil
+}
+
+// ResolveProject returns the ID of the project given by ID, path or web URL, see
+// ParseReference. In offline mode, paths are resolved from the saved snapshots.
+func (a *App) ResolveProject(ctx context.Context, ref string) (int64, error) {
+	id, path, err := a.ParseReference(ref)
+	if err != nil || id != 0 {
+		return id, err
+	}
+	if a.snapshots != nil {
+		return a.snapshots.FindPath(a.host, ScopeProject, path)
+	}
+	project, _, err := a.gitlabClient.Projects.GetProject(path, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project %s: %w", path, err)
+	}
+	return project.ID, nil
+}
+
+// ResolveGroup returns the ID of the group given by ID, path or web URL, see
+// ParseReference. In offline mode, paths are resolved from the saved snapshots.
+func (a *App) ResolveGroup(ctx context.Context, ref string) (int64, error) {
+	id, path, err := a.ParseReference(ref)
+	if err != nil || id != 0 {
+		return id, err
+	}
+	if a.snapshots != nil {
+		return a.snapshots.FindPath(a.host, ScopeGroup, path)
+	}
+	group, _, err := a.gitlabClient.Groups.GetGroup(path, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get group %s: %w", path, err)
+	}
+	return group.ID, nil
+}
+
+// resolvePaths replaces the project or group path of g with its ID.
+func (a *App) resolvePaths(ctx context.Context, g *GetIssues) error {
+	var err error
+	if g.ProjectPath != "" && g.ProjectID == 0 {
+		g.ProjectID, err = a.ResolveProject(ctx, g.ProjectPath)
+	}
+	if err == nil && g.GroupPath != "" && g.GroupID == 0 {
+		g.GroupID, err = a.ResolveGroup(ctx, g.GroupPath)
+	}
+	return err
+}
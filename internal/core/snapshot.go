@@ -0,0 +1,235 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// snapshotVersion is the version of the snapshot file format.
+const snapshotVersion = 1
+
+var (
+	errSnapshotNotFound = errors.New("no snapshot saved")
+	errSnapshotVersion  = errors.New("unsupported snapshot file version")
+	errOfflineQuery     = errors.New("search, started or upcoming milestones and scopes are not available offline")
+)
+
+// Snapshot holds every issue of a project or group along with the paths needed to
+// render them, so that reports can be built without any network call.
+type Snapshot struct {
+	Version      int              `json:"version"`
+	SavedAt      time.Time        `json:"saved_at"`
+	Host         string           `json:"host"`
+	Scope        string           `json:"scope"` // ScopeProject or ScopeGroup
+	ID           int64            `json:"id"`
+	Path         string           `json:"path"` // Project path with namespace or group full path
+	ProjectPaths map[int64]string `json:"project_paths"`
+	Issues       []*gitlab.Issue  `json:"issues"`
+}
+
+// SnapshotStore stores snapshots on disk, one file per project or group and GitLab instance.
+type SnapshotStore struct {
+	dir string
+}
+
+// NewSnapshotStore creates a snapshot store in dir. The directory is created on the first save.
+func NewSnapshotStore(dir string) *SnapshotStore {
+	return &SnapshotStore{dir: dir}
+}
+
+// DefaultSnapshotDir returns the default snapshot directory, under the user data
+// directory ($XDG_DATA_HOME, ~/.local/share by default). Snapshots are kept apart
+// from the cache as they must survive a cache cleanup.
+func DefaultSnapshotDir() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate user data directory: %w", err)
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "gitlab-issue-report", "snapshots"), nil
+}
+
+// Dir returns the directory of the store.
+func (s *SnapshotStore) Dir() string {
+	return s.dir
+}
+
+// Save writes the snapshot, replacing the previous snapshot of the same project or group.
+func (s *SnapshotStore) Save(snapshot *Snapshot) error {
+	path := s.path(snapshot.Host, snapshot.Scope, snapshot.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	snapshot.Version = snapshotVersion
+	if err := json.NewEncoder(tmp).Encode(snapshot); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return nil
+}
+
+// Load reads the snapshot of a project or group of a GitLab instance.
+func (s *SnapshotStore) Load(host, scope string, id int64) (*Snapshot, error) {
+	data, err := os.ReadFile(s.path(host, scope, id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %d of %s (see snapshot save)", errSnapshotNotFound, scope, id, host)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot file: %w", err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return nil, fmt.Errorf("%w: %d", errSnapshotVersion, snapshot.Version)
+	}
+	return &snapshot, nil
+}
+
+// FindPath returns the ID of the project or group whose snapshot has the given path,
+// compared case-insensitively as GitLab does.
+func (s *SnapshotStore) FindPath(host, scope, path string) (int64, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, hostDir(host), scope+"-*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), scope+"-"), ".json")
+		id, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		snapshot, err := s.Load(host, scope, id)
+		if err != nil {
+			return 0, err
+		}
+		if strings.EqualFold(snapshot.Path, path) {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("%w for %s %s of %s (see snapshot save)", errSnapshotNotFound, scope, path, host)
+}
+
+// path returns the path of the snapshot file of a project or group of a GitLab instance.
+func (s *SnapshotStore) path(host, scope string, id int64) string {
+	return filepath.Join(s.dir, hostDir(host), fmt.Sprintf("%s-%d.json", scope, id))
+}
+
+// SaveSnapshot fetches every issue of a project or group, whatever its state, with the
+// paths of the project or group and of the projects of the issues, and saves them in store.
+// opts can tune the download, e.g. WithConcurrency; filters must not be passed.
+func (a *App) SaveSnapshot(
+	ctx context.Context, store *SnapshotStore, scope string, id int64, opts ...GetIssuesOption,
+) (*Snapshot, error) {
+	snapshot := &Snapshot{SavedAt: time.Now(), Host: a.host, Scope: scope, ID: id}
+
+	var err error
+	if scope == ScopeGroup {
+		if snapshot.Path, err = a.GetGroupPath(ctx, id); err != nil {
+			return nil, err
+		}
+		if snapshot.ProjectPaths, err = a.GetGroupProjectPaths(ctx, id); err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithGroupID(id))
+	} else {
+		if snapshot.Path, err = a.GetProjectPath(ctx, id); err != nil {
+			return nil, err
+		}
+		snapshot.ProjectPaths = map[int64]string{id: snapshot.Path}
+		opts = append(opts, WithProjectID(id))
+	}
+
+	if snapshot.Issues, err = a.GetIssues(ctx, append(opts, WithState("all"))...); err != nil {
+		return nil, err
+	}
+
+	// Projects outside the group listing, e.g. issues moved from another project
+	var missing []*gitlab.Issue
+	for _, issue := range snapshot.Issues {
+		if _, ok := snapshot.ProjectPaths[issue.ProjectID]; !ok {
+			missing = append(missing, issue)
+		}
+	}
+	if len(missing) > 0 {
+		projectPaths, err := a.GetProjectPathsForIssues(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(snapshot.ProjectPaths, projectPaths)
+	}
+
+	if err := store.Save(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// snapshotPath returns the path of a project or group saved in the snapshots.
+func (a *App) snapshotPath(scope string, id int64) (string, error) {
+	snapshot, err := a.snapshots.Load(a.host, scope, id)
+	if err != nil {
+		return "", err
+	}
+	return snapshot.Path, nil
+}
+
+// snapshotProjectPaths returns the paths of the projects of the issues known by the
+// snapshots of the projects. Unknown projects are reported by ID.
+func (a *App) snapshotProjectPaths(issues []*gitlab.Issue) map[int64]string {
+	projectPaths := make(map[int64]string)
+	for _, issue := range issues {
+		if _, ok := projectPaths[issue.ProjectID]; ok {
+			continue
+		}
+		path, err := a.snapshotPath(ScopeProject, issue.ProjectID)
+		if err != nil {
+			path = fmt.Sprintf("ID:%d", issue.ProjectID)
+		}
+		projectPaths[issue.ProjectID] = path
+	}
+	return projectPaths
+}
+
+// streamSnapshotIssues passes the issues of the snapshot selected by g matching g to fn.
+func (a *App) streamSnapshotIssues(g *GetIssues, fn IssuePageFunc) error {
+	if !g.matchesLocally() {
+		return errOfflineQuery
+	}
+	scope, id := ScopeProject, g.ProjectID
+	if g.ProjectID == 0 {
+		scope, id = ScopeGroup, g.GroupID
+	}
+	snapshot, err := a.snapshots.Load(a.host, scope, id)
+	if err != nil {
+		return err
+	}
+	return emitIssues(filterIssues(snapshot.Issues, g), fn)
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+var (
+	errNoCACertificate = errors.New("no PEM certificate found in CA file")
+	errClientKeyPair   = errors.New("client certificate and key must be given together")
+	errInvalidProxyURL = errors.New("invalid proxy URL")
+)
+
+// newHTTPTransport returns the transport of the GitLab clients: the default transport
+// with the CA bundle, client certificate, proxy and certificate verification of opts.
+func newHTTPTransport(opts ClientOptions) (*http.Transport, error) {
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		defaultTransport = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+	transport := defaultTransport.Clone()
+
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("%w: %q, expected e.g. http://proxy.example.com:3128", errInvalidProxyURL, opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if opts.CACert == "" && opts.ClientCert == "" && opts.ClientKey == "" && !opts.InsecureSkipVerify {
+		return transport, nil
+	}
+	// InsecureSkipVerify is only set on request, the command warns about it
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+	if opts.CACert != "" {
+		pool, err := certPool(opts.CACert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, errClientKeyPair
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// certPool returns the system certificate pool extended with the PEM certificates of
+// the file at path, so that public hosts keep working next to the internal CA.
+func certPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: %s", errNoCACertificate, path)
+	}
+	return pool, nil
+}
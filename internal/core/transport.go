@@ -0,0 +1,263 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Default retry settings of the HTTP layer.
+const (
+	DefaultMaxRetries = 5
+	DefaultRetryWait  = time.Second
+	maxRetryWait      = 2 * time.Minute
+)
+
+// Rate limit headers sent by GitLab.
+const (
+	headerRateLimit     = "RateLimit-Limit"
+	headerRateRemaining = "RateLimit-Remaining"
+	headerRateReset     = "RateLimit-Reset"
+	headerRetryAfter    = "Retry-After"
+)
+
+// ClientOptions configures the HTTP layer shared by every GitLab client of the application.
+type ClientOptions struct {
+	Timeout    time.Duration // Timeout of a single request attempt, 0 for none
+	MaxRetries int           // Retries of a request answered with 429 or 5xx
+	RetryWait  time.Duration // Initial backoff, doubled after each retry
+
+	CACert             string // PEM file of CAs trusted in addition to the system ones
+	ClientCert         string // PEM certificate presented for mutual TLS, with ClientKey
+	ClientKey          string // PEM private key of ClientCert
+	Proxy              string // Proxy URL, HTTPS_PROXY and related variables when empty
+	InsecureSkipVerify bool   // Accept any server certificate, for testing only
+}
+
+// NewGitlabClient creates a GitLab client using the rate-limit aware HTTP transport.
+// The retries of the GitLab client itself are disabled in favour of the transport.
+func NewGitlabClient(token, uri string, opts ClientOptions) (*gitlab.Client, error) {
+	transport, err := newHTTPTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{
+		Transport: newRateLimitTransport(transport, opts),
+	}
+
+	gitlabClient, err := gitlab.NewClient(
+		token,
+		gitlab.WithBaseURL(uri),
+		gitlab.WithHTTPClient(httpClient),
+		gitlab.WithoutRetries(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+	}
+	return gitlabClient, nil
+}
+
+// rateLimitTransport is an http.RoundTripper that paces requests when the rate limit
+// is about to be exhausted and retries requests answered with 429 or 5xx.
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	timeout    time.Duration
+	maxRetries int
+	retryWait  time.Duration
+	sleep      func(ctx context.Context, d time.Duration) error
+
+	mu        sync.Mutex
+	notBefore time.Time // Next request is delayed until then to stay under the rate limit
+}
+
+// newRateLimitTransport wraps base with rate limiting and retries.
+func newRateLimitTransport(base http.RoundTripper, opts ClientOptions) *rateLimitTransport {
+	retryWait := opts.RetryWait
+	if retryWait <= 0 {
+		retryWait = DefaultRetryWait
+	}
+	return &rateLimitTransport{
+		base:       base,
+		timeout:    opts.Timeout,
+		maxRetries: max(opts.MaxRetries, 0),
+		retryWait:  retryWait,
+		sleep:      sleepContext,
+	}
+}
+
+// RoundTrip sends the request, retrying it on 429 and 5xx responses.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForRateLimit(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.roundTripOnce(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		t.observe(resp.Header)
+
+		if !isRetryableStatus(resp.StatusCode) || attempt >= t.maxRetries || !canRetry(req) {
+			return resp, nil
+		}
+
+		wait := t.retryDelay(resp, attempt)
+		logrus.Warnf("GitLab answered %s for %s, retrying in %v (attempt %d/%d)",
+			resp.Status, req.URL.Path, wait.Round(time.Millisecond), attempt+1, t.maxRetries)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTripOnce sends a single attempt with the per-attempt timeout. The timeout
+// also covers reading the response body and is released when the body is closed.
+func (t *rateLimitTransport) roundTripOnce(req *http.Request, attempt int) (*http.Response, error) {
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// waitForRateLimit delays the request when the previous response reported
+// that the rate limit was nearly exhausted.
+func (t *rateLimitTransport) waitForRateLimit(ctx context.Context) error {
+	t.mu.Lock()
+	wait := time.Until(t.notBefore)
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	logrus.Debugf("Rate limit nearly reached, waiting %v", wait.Round(time.Millisecond))
+	return t.sleep(ctx, wait)
+}
+
+// observe paces the next requests from the rate limit headers: once less than a tenth
+// of the limit remains, the remaining requests are spread until the limit resets.
+func (t *rateLimitTransport) observe(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get(headerRateRemaining))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get(headerRateLimit))
+	reset, err := strconv.ParseInt(header.Get(headerRateReset), 10, 64)
+	if err != nil || remaining > max(limit/10, 1) {
+		return
+	}
+
+	untilReset := time.Until(time.Unix(reset, 0))
+	if untilReset <= 0 {
+		return
+	}
+	delay := untilReset / time.Duration(remaining+1)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if next := time.Now().Add(delay); next.After(t.notBefore) {
+		t.notBefore = next
+	}
+}
+
+// retryDelay returns how long to wait before retrying. Retry-After and RateLimit-Reset
+// are honoured when present, otherwise the wait grows exponentially with jitter.
+func (t *rateLimitTransport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if wait, ok := parseRetryAfter(resp.Header.Get(headerRetryAfter)); ok {
+		return min(wait, maxRetryWait)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if reset, err := strconv.ParseInt(resp.Header.Get(headerRateReset), 10, 64); err == nil {
+			if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+				return min(wait+jitter(t.retryWait), maxRetryWait)
+			}
+		}
+	}
+
+	backoff := min(t.retryWait<<attempt, maxRetryWait)
+	// Equal jitter: half of the backoff is fixed, the other half random
+	return backoff/2 + jitter(backoff/2)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// isRetryableStatus reports whether a response status is worth retrying.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// canRetry reports whether the request body can be sent again.
+func canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// jitter returns a random duration in [0, d).
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("request cancelled while waiting: %w", ctx.Err())
+	}
+}
+
+// cancelOnClose releases the context of a request attempt once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the attempt context.
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	if err := c.ReadCloser.Close(); err != nil {
+		return fmt.Errorf("failed to close response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// scriptedServer answers each request with the next status and headers of its script,
+// repeating the last one, and records the body of each request.
+type scriptedServer struct {
+	url    string
+	mu     sync.Mutex
+	script []scriptedResponse
+	bodies []string
+}
+
+type scriptedResponse struct {
+	status int
+	header map[string]string
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	response := s.script[min(len(s.bodies), len(s.script)-1)]
+	s.bodies = append(s.bodies, string(body))
+	s.mu.Unlock()
+	for key, value := range response.header {
+		w.Header().Set(key, value)
+	}
+	w.WriteHeader(response.status)
+}
+
+// requests returns the number of requests served.
+func (s *scriptedServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+// body returns the body of the i-th request served.
+func (s *scriptedServer) body(i int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bodies[i]
+}
+
+// newTestTransport returns a transport sending requests to a server following script,
+// and the waits it went through instead of sleeping.
+func newTestTransport(
+	t *testing.T,
+	opts ClientOptions,
+	script ...scriptedResponse,
+) (*rateLimitTransport, *scriptedServer, *[]time.Duration) {
+	t.Helper()
+	server := &scriptedServer{script: script}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	server.url = httpServer.URL + "/api/v4/issues"
+
+	transport := newRateLimitTransport(http.DefaultTransport, opts)
+	waits := &[]time.Duration{}
+	transport.sleep = func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return transport, server, waits
+}
+
+// get sends a GET request to server through transport and closes the response.
+func get(t *testing.T, transport http.RoundTripper, server *scriptedServer) int {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestTransportRetriesTooManyRequestsAfterRetryAfter(t *testing.T) {
+	transport, server, waits := newTestTransport(t, ClientOptions{MaxRetries: 3},
+		scriptedResponse{status: http.StatusTooManyRequests, header: map[string]string{headerRetryAfter: "7"}},
+		scriptedResponse{status: http.StatusOK},
+	)
+
+	if status := get(t, transport, server); status != http.StatusOK {
+		t.Errorf("status = %d, want 200 after the retry", status)
+	}
+	if server.requests() != 2 || len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("%d requests, waits %v, want 2 requests and a wait of 7s", server.requests(), *waits)
+	}
+}
+
+func TestTransportSurfacesServerErrorsAfterMaxRetries(t *testing.T) {
+	transport, server, waits := newTestTransport(t, ClientOptions{MaxRetries: 3},
+		scriptedResponse{status: http.StatusServiceUnavailable},
+	)
+
+	if status := get(t, transport, server); status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want the last 503", status)
+	}
+	if server.requests() != 4 || len(*waits) != 3 {
+		t.Errorf("%d requests, %d waits, want 4 requests and 3 waits", server.requests(), len(*waits))
+	}
+
+	// The GitLab client reports the last response as an error
+	client, err := NewGitlabClient("token", strings.TrimSuffix(server.url, "/api/v4/issues"),
+		ClientOptions{MaxRetries: 1, RetryWait: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, resp, err := client.Issues.ListIssues(nil, gitlab.WithContext(t.Context()))
+	if err == nil || resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("ListIssues() = %v, want the 503 as an error", err)
+	}
+	if server.requests() != 6 {
+		t.Errorf("%d requests, want 2 more from the GitLab client", server.requests())
+	}
+
+	// Client errors are not retried
+	transport, server, _ = newTestTransport(t, ClientOptions{MaxRetries: 3},
+		scriptedResponse{status: http.StatusNotFound},
+	)
+	if status := get(t, transport, server); status != http.StatusNotFound || server.requests() != 1 {
+		t.Errorf("status = %d after %d requests, want a single 404", status, server.requests())
+	}
+}
+
+func TestTransportBackoffBoundedByRetryWait(t *testing.T) {
+	retryWait := 100 * time.Millisecond
+	transport, server, waits := newTestTransport(t, ClientOptions{MaxRetries: 4, RetryWait: retryWait},
+		scriptedResponse{status: http.StatusBadGateway},
+	)
+
+	get(t, transport, server)
+	if len(*waits) != 4 {
+		t.Fatalf("waits = %v, want 4", *waits)
+	}
+	for attempt, wait := range *waits {
+		backoff := retryWait << attempt
+		if wait < backoff/2 || wait >= backoff {
+			t.Errorf("wait of attempt %d = %v, want in [%v, %v)", attempt, wait, backoff/2, backoff)
+		}
+	}
+
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}}
+	if wait := transport.retryDelay(resp, 30); wait > maxRetryWait {
+		t.Errorf("retryDelay() after 30 attempts = %v, want at most %v", wait, maxRetryWait)
+	}
+	resp.Header.Set(headerRetryAfter, "3600")
+	if wait := transport.retryDelay(resp, 0); wait != maxRetryWait {
+		t.Errorf("retryDelay() with Retry-After: 3600 = %v, want %v", wait, maxRetryWait)
+	}
+}
+
+func TestTransportPacesWhenRateLimitIsLow(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(60*time.Second).Unix(), 10)
+	tests := []struct {
+		name      string
+		remaining string
+		wantWait  bool
+	}{
+		{name: "plenty left", remaining: "50"},
+		{name: "nearly exhausted", remaining: "5", wantWait: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, server, waits := newTestTransport(t, ClientOptions{},
+				scriptedResponse{status: http.StatusOK, header: map[string]string{
+					headerRateLimit: "100", headerRateRemaining: tt.remaining, headerRateReset: reset,
+				}},
+			)
+
+			get(t, transport, server)
+			get(t, transport, server)
+			if !tt.wantWait {
+				if len(*waits) != 0 {
+					t.Errorf("waits = %v, want none", *waits)
+				}
+				return
+			}
+			// The minute until the reset is spread over the 5 remaining requests
+			if len(*waits) != 1 || (*waits)[0] < 8*time.Second || (*waits)[0] > 10*time.Second {
+				t.Errorf("waits = %v, want a single wait of about 10s", *waits)
+			}
+		})
+	}
+}
+
+func TestTransportReplaysBodyOnRetry(t *testing.T) {
+	transport, server, _ := newTestTransport(t, ClientOptions{MaxRetries: 2},
+		scriptedResponse{status: http.StatusInternalServerError},
+		scriptedResponse{status: http.StatusCreated},
+	)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.url,
+		bytes.NewReader([]byte(`{"title":"retried"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || server.requests() != 2 ||
+		server.body(1) != `{"title":"retried"}` {
+		t.Errorf("status %d after %d requests, want 201 and the body sent twice", resp.StatusCode, server.requests())
+	}
+
+	// A body that cannot be rewound is not retried
+	transport, server, _ = newTestTransport(t, ClientOptions{MaxRetries: 2},
+		scriptedResponse{status: http.StatusInternalServerError},
+	)
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodPost, server.url,
+		io.NopCloser(bytes.NewReader([]byte("once"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if server.requests() != 1 {
+		t.Errorf("%d requests with a one-shot body, want 1", server.requests())
+	}
+}
+
+func TestTransportStopsWaitingWhenCancelled(t *testing.T) {
+	transport, server, _ := newTestTransport(t, ClientOptions{MaxRetries: 3},
+		scriptedResponse{status: http.StatusServiceUnavailable},
+	)
+	transport.sleep = sleepContext
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() error = %v, want context.Canceled", err)
+	}
+	if server.requests() > 1 {
+		t.Errorf("%d requests after cancellation, want at most 1", server.requests())
+	}
+}
@@ -0,0 +1,227 @@
+package render
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+var errUnknownColumn = errors.New("unknown column")
+
+// Column describes a report column shared by the tabular renderers.
+type Column struct {
+	Name        string // Identifier used by the --columns flag, e.g. "iid"
+	Header      string // Human readable header, e.g. "IID"
+	tableHeader string // Header of the table format when it differs, e.g. "CreatedAt"
+	width       int    // Column width in plain output (0 means unpadded)
+	alignRight  bool   // Right-align the value in plain output
+	headerLeft  bool   // Left-align the header in plain output, even with alignRight
+	value       func(issue *gitlab.Issue, context *Context) string
+}
+
+// dateColumn returns a date column. Dates are right-aligned under a left-aligned header
+// in plain output, and the table format uses compact headers such as "CreatedAt".
+func dateColumn(name, header, tableHeader string, value func(issue *gitlab.Issue, context *Context) string) Column {
+	return Column{
+		Name: name, Header: header, tableHeader: tableHeader,
+		width: 12, alignRight: true, headerLeft: true, value: value,
+	}
+}
+
+// formatDate formats an optional timestamp as a date, returning an empty string when unset.
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// Value returns the cell value of the column for an issue.
+func (c Column) Value(issue *gitlab.Issue, context *Context) string {
+	return c.value(issue, context)
+}
+
+// columnRegistry lists every column available to renderers, in display order.
+var columnRegistry = []Column{
+	{Name: "project", Header: "Project", width: 40, value: projectValue},
+	{Name: "iid", Header: "IID", width: 6, alignRight: true, value: func(i *gitlab.Issue, _ *Context) string {
+		return strconv.FormatInt(i.IID, 10)
+	}},
+	{Name: "title", Header: "Title", width: maxTitleLength, value: func(i *gitlab.Issue, _ *Context) string {
+		return i.Title
+	}},
+	{Name: "state", Header: "State", width: 10, alignRight: true, value: func(i *gitlab.Issue, _ *Context) string {
+		return i.State
+	}},
+	dateColumn("created", "Created At", "CreatedAt", func(i *gitlab.Issue, _ *Context) string {
+		return formatDate(i.CreatedAt)
+	}),
+	dateColumn("updated", "Updated At", "UpdatedAt", func(i *gitlab.Issue, _ *Context) string {
+		return formatDate(i.UpdatedAt)
+	}),
+	dateColumn("closed", "Closed At", "ClosedAt", func(i *gitlab.Issue, _ *Context) string {
+		return formatDate(i.ClosedAt)
+	}),
+	dateColumn("due", "Due Date", "DueDate", func(i *gitlab.Issue, _ *Context) string {
+		if i.DueDate == nil {
+			return ""
+		}
+		return i.DueDate.String()
+	}),
+	{Name: "author", Header: "Author", width: 20, value: func(i *gitlab.Issue, _ *Context) string {
+		if i.Author == nil {
+			return ""
+		}
+		return i.Author.Username
+	}},
+	{Name: "assignees", Header: "Assignees", width: 25, value: func(i *gitlab.Issue, _ *Context) string {
+		return formatAssignees(i.Assignees)
+	}},
+	{Name: "milestone", Header: "Milestone", width: 20, value: func(i *gitlab.Issue, _ *Context) string {
+		if i.Milestone == nil {
+			return ""
+		}
+		return i.Milestone.Title
+	}},
+	{Name: "weight", Header: "Weight", width: 6, alignRight: true, value: func(i *gitlab.Issue, _ *Context) string {
+		return strconv.FormatInt(i.Weight, 10)
+	}},
+	{Name: "labels", Header: "Labels", width: 30, value: func(i *gitlab.Issue, _ *Context) string {
+		return formatLabels(i.Labels)
+	}},
+	{Name: "url", Header: "URL", width: 60, value: func(i *gitlab.Issue, _ *Context) string {
+		return i.WebURL
+	}},
+}
+
+// defaultColumnNames are the columns shown when none are selected.
+var defaultColumnNames = []string{"title", "state", "created", "updated", "labels"}
+
+// ColumnNames returns the names of all available columns.
+func ColumnNames() []string {
+	names := make([]string, 0, len(columnRegistry))
+	for _, c := range columnRegistry {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// ParseColumns resolves column names to columns, preserving the requested order.
+func ParseColumns(names []string) ([]Column, error) {
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		column, ok := lookupColumn(strings.ToLower(strings.TrimSpace(name)))
+		if !ok {
+			return nil, fmt.Errorf("%w: %q (available: %s)", errUnknownColumn, name,
+				strings.Join(ColumnNames(), ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// DefaultColumns returns the default columns for a context.
+// Group and multi-project reports get a leading project column.
+func DefaultColumns(context *Context) []Column {
+	names := defaultColumnNames
+	if context.spansProjects() {
+		names = append([]string{"project"}, names...)
+	}
+	columns, _ := ParseColumns(names)
+	return columns
+}
+
+// lookupColumn returns the registered column with the given name.
+func lookupColumn(name string) (Column, bool) {
+	for _, c := range columnRegistry {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// columnSet holds the user-selected columns of a renderer.
+// It is embedded by every tabular renderer.
+type columnSet struct {
+	columns []Column
+}
+
+// SetColumns selects the columns to render. An empty selection restores the defaults.
+func (s *columnSet) SetColumns(columns []Column) {
+	s.columns = columns
+}
+
+// resolve returns the selected columns, or the defaults for the context.
+func (s *columnSet) resolve(context *Context) []Column {
+	if len(s.columns) > 0 {
+		return s.columns
+	}
+	return DefaultColumns(context)
+}
+
+// ColumnSetter is implemented by renderers whose columns can be selected.
+type ColumnSetter interface {
+	SetColumns(columns []Column)
+}
+
+// headers returns the headers of the columns.
+func headers(columns []Column) []string {
+	out := make([]string, 0, len(columns))
+	for _, c := range columns {
+		out = append(out, c.Header)
+	}
+	return out
+}
+
+// tableHeaders returns the headers of the table format for the columns.
+func tableHeaders(columns []Column) []string {
+	out := make([]string, 0, len(columns))
+	for _, c := range columns {
+		out = append(out, cmp.Or(c.tableHeader, c.Header))
+	}
+	return out
+}
+
+// row returns the cell values of an issue for the columns.
+func row(columns []Column, issue *gitlab.Issue, context *Context) []string {
+	out := make([]string, 0, len(columns))
+	for _, c := range columns {
+		out = append(out, c.Value(issue, context))
+	}
+	return out
+}
+
+// hasColumn reports whether the named column is part of the selection.
+func hasColumn(columns []Column, name string) bool {
+	for _, c := range columns {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// projectValue returns the project path of an issue, falling back to its ID.
+func projectValue(issue *gitlab.Issue, context *Context) string {
+	if path := context.projectPathFor(issue.ProjectID); path != "" {
+		return path
+	}
+	return fmt.Sprintf("ID:%d", issue.ProjectID)
+}
+
+// formatAssignees joins assignee usernames with ", " for display.
+func formatAssignees(assignees []*gitlab.IssueAssignee) string {
+	names := make([]string, 0, len(assignees))
+	for _, a := range assignees {
+		if a != nil {
+			names = append(names, a.Username)
+		}
+	}
+	return strings.Join(names, ", ")
+}
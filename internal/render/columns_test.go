@@ -0,0 +1,188 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          []string
+		want        []string
+		expectError bool
+	}{
+		{name: "single column", in: []string{"iid"}, want: []string{"iid"}},
+		{name: "keeps requested order", in: []string{"url", "iid", "title"}, want: []string{"url", "iid", "title"}},
+		{name: "trims and lowercases", in: []string{" IID ", "Title"}, want: []string{"iid", "title"}},
+		{name: "unknown column", in: []string{"iid", "nope"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := ParseColumns(tt.in)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("ParseColumns() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseColumns() unexpected error = %v", err)
+			}
+			if len(columns) != len(tt.want) {
+				t.Fatalf("ParseColumns() returned %d columns, want %d", len(columns), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if columns[i].Name != name {
+					t.Errorf("column[%d] = %q, want %q", i, columns[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultColumns(t *testing.T) {
+	project := DefaultColumns(NewProjectContext("ns/project"))
+	if hasColumn(project, "project") {
+		t.Error("project report should not have a project column by default")
+	}
+
+	group := DefaultColumns(NewGroupContext("ns", nil))
+	if len(group) == 0 || group[0].Name != "project" {
+		t.Error("group report should start with a project column by default")
+	}
+
+	projects := DefaultColumns(NewProjectsContext([]string{"ns/a", "other/b"}, nil))
+	if len(projects) == 0 || projects[0].Name != "project" {
+		t.Error("multi-project report should start with a project column by default")
+	}
+}
+
+func TestColumnValues(t *testing.T) {
+	due := gitlab.ISOTime(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	issue := createTestIssues()[0]
+	issue.IID = 42
+	issue.ProjectID = 100
+	issue.WebURL = "https://gitlab.example.com/ns/a/-/issues/42"
+	issue.Weight = 3
+	issue.DueDate = &due
+	issue.Author = &gitlab.IssueAuthor{Username: "alice"}
+	issue.Assignees = []*gitlab.IssueAssignee{{Username: "bob"}, {Username: "carol"}}
+	issue.Milestone = &gitlab.Milestone{Title: "v1.0"}
+	context := NewGroupContext("ns", map[int64]string{100: "ns/a"})
+
+	expected := map[string]string{
+		"project":   "ns/a",
+		"iid":       "42",
+		"due":       "2024-02-01",
+		"author":    "alice",
+		"assignees": "bob, carol",
+		"milestone": "v1.0",
+		"weight":    "3",
+		"labels":    "bug, backend",
+		"url":       "https://gitlab.example.com/ns/a/-/issues/42",
+		"closed":    "",
+	}
+	for name, want := range expected {
+		column, ok := lookupColumn(name)
+		if !ok {
+			t.Fatalf("column %q not registered", name)
+		}
+		if got := column.Value(issue, context); got != want {
+			t.Errorf("column %q = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestRenderers_SelectedColumns verifies every tabular renderer honours the selected columns.
+func TestRenderers_SelectedColumns(t *testing.T) {
+	issues := createTestIssues()
+	issues[0].IID = 42
+	issues[0].Assignees = []*gitlab.IssueAssignee{{Username: "bob"}}
+
+	columns, err := ParseColumns([]string{"iid", "title", "assignees"})
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		renderer   Renderer
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "plain",
+			renderer:   NewPlainRenderer(true),
+			expected:   []string{"IID", "Assignees", "42", "bob"},
+			unexpected: []string{"Created At"},
+		},
+		{
+			name:       "table",
+			renderer:   NewTableRenderer(),
+			expected:   []string{"IID", "ASSIGNEES", "42", "bob"},
+			unexpected: []string{"CREATED AT"},
+		},
+		{
+			name:       "markdown",
+			renderer:   NewMarkdownRenderer(),
+			expected:   []string{"| IID | Title | Assignees |", "|-----|-------|-----------|", "| 42 | Fix authentication bug | bob |"},
+			unexpected: []string{"Created At"},
+		},
+		{
+			name:       "csv",
+			renderer:   NewCSVRenderer(),
+			expected:   []string{"IID,Title,Assignees\n", "42,Fix authentication bug,bob\n"},
+			unexpected: []string{"Created At"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setter, ok := tt.renderer.(ColumnSetter)
+			if !ok {
+				t.Fatalf("%s renderer does not support column selection", tt.name)
+			}
+			setter.SetColumns(columns)
+
+			var buf bytes.Buffer
+			if err := tt.renderer.Render(issues, &buf); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			output := buf.String()
+			for _, exp := range tt.expected {
+				if !strings.Contains(output, exp) {
+					t.Errorf("output missing %q\nGot:\n%s", exp, output)
+				}
+			}
+			for _, unexp := range tt.unexpected {
+				if strings.Contains(output, unexp) {
+					t.Errorf("output unexpectedly contains %q\nGot:\n%s", unexp, output)
+				}
+			}
+		})
+	}
+}
+
+func TestPlainRenderer_DateLayout(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	issues := []*gitlab.Issue{{Title: "Fix bug", State: "opened", CreatedAt: &day, UpdatedAt: &day}}
+
+	var buf bytes.Buffer
+	if err := NewPlainRenderer(true).Render(issues, &buf); err != nil {
+		t.Fatal(err)
+	}
+	// Dates are right-aligned under left-aligned headers
+	want := fmt.Sprintf("%-70s %10s %-12s %-12s %s\n%-70s %10s %12s %12s %s\n",
+		"Title", "State", "Created At", "Updated At", "Labels",
+		"Fix bug", "opened", "2024-01-02", "2024-01-02", "")
+	if got := buf.String(); got != want {
+		t.Errorf("Render() =\n%q\nwant\n%q", got, want)
+	}
+}
@@ -1,5 +1,7 @@
 package render
 
+import "strings"
+
 // SourceType represents the source of issues (project or group).
 type SourceType string
 
@@ -8,14 +10,21 @@ const (
 	SourceTypeProject SourceType = "project"
 	// SourceTypeGroup indicates issues from a group (potentially multiple projects).
 	SourceTypeGroup SourceType = "group"
+	// SourceTypeProjects indicates issues from several projects given one by one.
+	SourceTypeProjects SourceType = "projects"
+	// SourceTypeInstance indicates issues of the whole GitLab instance in a scope.
+	SourceTypeInstance SourceType = "instance"
 )
 
 // Context provides contextual information for rendering issues.
 type Context struct {
-	Source      SourceType       // "project" or "group"
-	ProjectPath string           // For single project, e.g., "namespace/project"
-	GroupPath   string           // For group queries, e.g., "namespace/group"
-	ProjectMap  map[int64]string // Maps ProjectID -> PathWithNamespace for multi-project scenarios
+	Source      SourceType        // "project" or "group"
+	ProjectPath string            // For single project, e.g., "namespace/project"
+	GroupPath   string            // For group queries, e.g., "namespace/group"
+	Projects    []string          // For multi-project reports, the paths in the order given
+	Scope       string            // For instance reports, e.g., "assigned_to_me"
+	ProjectMap  map[int64]string  // Maps ProjectID -> PathWithNamespace for multi-project scenarios
+	Query       map[string]string // Query parameters used to fetch the issues (flag name -> value)
 }
 
 // NewProjectContext creates context for single-project rendering.
@@ -34,3 +43,67 @@ func NewGroupContext(groupPath string, projectMap map[int64]string) *Context {
 		ProjectMap: projectMap,
 	}
 }
+
+// NewProjectsContext creates context for rendering the issues of several projects,
+// given by their paths, in one report. Like group reports, it has a project column.
+func NewProjectsContext(projects []string, projectMap map[int64]string) *Context {
+	return &Context{
+		Source:     SourceTypeProjects,
+		Projects:   projects,
+		ProjectMap: projectMap,
+	}
+}
+
+// NewInstanceContext creates context for rendering the issues of the whole instance
+// in scope, e.g. "assigned_to_me". Like group reports, it has a project column.
+func NewInstanceContext(scope string, projectMap map[int64]string) *Context {
+	return &Context{
+		Source:     SourceTypeInstance,
+		Scope:      scope,
+		ProjectMap: projectMap,
+	}
+}
+
+// spansProjects reports whether the issues of the context can belong to several
+// projects, so that the project of each issue is shown.
+func (c *Context) spansProjects() bool {
+	if c == nil {
+		return false
+	}
+	switch c.Source {
+	case SourceTypeGroup, SourceTypeProjects, SourceTypeInstance:
+		return true
+	default:
+		return false
+	}
+}
+
+// title returns the name of the source of the issues used in report titles, e.g.
+// "Group: namespace/group".
+func (c *Context) title() string {
+	switch c.Source {
+	case SourceTypeProject:
+		return c.ProjectPath
+	case SourceTypeProjects:
+		return "Projects: " + strings.Join(c.Projects, ", ")
+	case SourceTypeInstance:
+		return "Scope: " + c.Scope
+	default:
+		return "Group: " + c.GroupPath
+	}
+}
+
+// projectPathFor returns the path of the project an issue belongs to, or an
+// empty string when the context does not know it.
+func (c *Context) projectPathFor(projectID int64) string {
+	if c == nil {
+		return ""
+	}
+	if path, ok := c.ProjectMap[projectID]; ok {
+		return path
+	}
+	if c.Source == SourceTypeProject {
+		return c.ProjectPath
+	}
+	return ""
+}
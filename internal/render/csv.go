@@ -0,0 +1,71 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// CSVRenderer renders issues as delimiter-separated values with RFC 4180 quoting.
+type CSVRenderer struct {
+	columnSet
+	summarySet
+	delimiter rune
+}
+
+// NewCSVRenderer creates a new CSVRenderer producing comma-separated values.
+func NewCSVRenderer() *CSVRenderer {
+	return &CSVRenderer{delimiter: ','}
+}
+
+// NewTSVRenderer creates a new CSVRenderer producing tab-separated values.
+func NewTSVRenderer() *CSVRenderer {
+	return &CSVRenderer{delimiter: '\t'}
+}
+
+// Render renders issues as delimiter-separated values.
+func (c *CSVRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
+	return c.RenderWithContext(issues, nil, writer)
+}
+
+// RenderWithContext renders issues as delimiter-separated values with contextual information.
+// Group reports get a leading Project column.
+func (c *CSVRenderer) RenderWithContext(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	if c.showIssues() {
+		columns := c.resolve(context)
+		records := make([][]string, 0, len(issues)+1)
+		records = append(records, headers(columns))
+		for _, issue := range issues {
+			records = append(records, row(columns, issue, context))
+		}
+		if err := c.writeRecords(records, writer); err != nil {
+			return err
+		}
+	}
+
+	// The summary is a separate Category/Key/Value table after an empty line
+	return c.writeSummaryWith(writer, func() error {
+		return c.writeRecords(NewSummary(issues, context, time.Now()).records(), writer)
+	})
+}
+
+// writeRecords writes records with the renderer's delimiter.
+func (c *CSVRenderer) writeRecords(records [][]string, writer io.Writer) error {
+	w := csv.NewWriter(writer)
+	w.Comma = c.delimiter
+
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to flush rows: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestCSVRenderer_Quoting(t *testing.T) {
+	issues := createTestIssues()
+	issues[0].Title = `Fix "auth", again`
+
+	var buf bytes.Buffer
+	if err := NewCSVRenderer().Render(issues, &buf); err != nil {
+		t.Fatalf("CSVRenderer.Render() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, exp := range []string{
+		"Title,State,Created At,Updated At,Labels\n",
+		`"Fix ""auth"", again",opened,`,
+		"\"Update documentation\nwith newlines\",opened,",
+		`,"bug, backend"`,
+	} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("output missing %q\nGot:\n%s", exp, output)
+		}
+	}
+
+	// The output must round-trip through a standard CSV reader.
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4 (header + 3 issues)", len(records))
+	}
+	if records[1][0] != `Fix "auth", again` {
+		t.Errorf("title = %q, want original title", records[1][0])
+	}
+	if records[3][0] != "Update documentation\nwith newlines" {
+		t.Errorf("title = %q, want embedded newline preserved", records[3][0])
+	}
+}
+
+func TestCSVRenderer_GroupProjectColumn(t *testing.T) {
+	context := NewGroupContext("my-group", map[int64]string{
+		100: "namespace/project-a",
+		200: "namespace/project-b",
+	})
+
+	var buf bytes.Buffer
+	if err := NewCSVRenderer().RenderWithContext(createTestIssuesWithProjects(), context, &buf); err != nil {
+		t.Fatalf("CSVRenderer.RenderWithContext() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if records[0][0] != "Project" {
+		t.Errorf("first header = %q, want Project", records[0][0])
+	}
+	if records[1][0] != "namespace/project-a" || records[2][0] != "namespace/project-b" {
+		t.Errorf("project column = %q, %q", records[1][0], records[2][0])
+	}
+}
+
+func TestTSVRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewTSVRenderer().Render(createTestIssues()[:1], &buf); err != nil {
+		t.Fatalf("TSVRenderer.Render() error = %v", err)
+	}
+
+	reader := csv.NewReader(&buf)
+	reader.Comma = '\t'
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid TSV: %v", err)
+	}
+	if len(records) != 2 || len(records[0]) != 5 {
+		t.Fatalf("unexpected TSV shape: %v", records)
+	}
+	if records[1][4] != "bug, backend" {
+		t.Errorf("labels = %q, want %q", records[1][4], "bug, backend")
+	}
+}
@@ -0,0 +1,161 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+var errUnknownGroupBy = errors.New("unknown group-by field")
+
+// noGroupKey is the section title of issues that have no value for the grouping field.
+const noGroupKey = "(none)"
+
+// Grouping splits issues into sections sharing the same value of a field.
+type Grouping struct {
+	Label string // Section label, e.g. "Assignee"
+	keys  func(issue *gitlab.Issue, context *Context) []string
+}
+
+// IssueGroup is a section of a grouped report.
+type IssueGroup struct {
+	Key    string
+	Issues []*gitlab.Issue
+}
+
+// groupingFields are the fixed fields issues can be grouped by.
+var groupingFields = map[string]Grouping{
+	"project": {Label: "Project", keys: func(i *gitlab.Issue, c *Context) []string {
+		return []string{projectValue(i, c)}
+	}},
+	"assignee": {Label: "Assignee", keys: func(i *gitlab.Issue, _ *Context) []string {
+		var keys []string
+		for _, a := range i.Assignees {
+			if a != nil {
+				keys = append(keys, a.Username)
+			}
+		}
+		return keys
+	}},
+	"milestone": {Label: "Milestone", keys: func(i *gitlab.Issue, _ *Context) []string {
+		if i.Milestone == nil {
+			return nil
+		}
+		return []string{i.Milestone.Title}
+	}},
+	"state": {Label: "State", keys: func(i *gitlab.Issue, _ *Context) []string {
+		return []string{i.State}
+	}},
+}
+
+// ParseGroupBy parses a group-by field. Besides the fixed fields (project, assignee,
+// milestone, state), a scoped label prefix such as "priority::" groups issues by
+// the value of that scoped label.
+func ParseGroupBy(field string) (*Grouping, error) {
+	field = strings.TrimSpace(field)
+	if grouping, ok := groupingFields[strings.ToLower(field)]; ok {
+		return &grouping, nil
+	}
+	if prefix, ok := strings.CutSuffix(field, "::"); ok && prefix != "" {
+		return &Grouping{Label: prefix, keys: func(i *gitlab.Issue, _ *Context) []string {
+			if value := scopedLabelValue(i.Labels, field); value != "" {
+				return []string{value}
+			}
+			return nil
+		}}, nil
+	}
+	return nil, fmt.Errorf("%w: %q (must be project, assignee, milestone, state or a scoped label prefix like priority::)",
+		errUnknownGroupBy, field)
+}
+
+// Group splits issues into sections ordered by key, with the section of issues
+// without a value last. An issue with several values (e.g. several assignees)
+// appears in each matching section. Issue order within a section is preserved.
+func (g *Grouping) Group(issues []*gitlab.Issue, context *Context) []IssueGroup {
+	index := make(map[string]int)
+	var groups []IssueGroup
+	for _, issue := range issues {
+		keys := g.keys(issue, context)
+		if len(keys) == 0 {
+			keys = []string{noGroupKey}
+		}
+		for _, key := range keys {
+			i, ok := index[key]
+			if !ok {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, IssueGroup{Key: key})
+			}
+			groups[i].Issues = append(groups[i].Issues, issue)
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b IssueGroup) int {
+		switch {
+		case a.Key == noGroupKey:
+			return 1
+		case b.Key == noGroupKey:
+			return -1
+		}
+		return strings.Compare(strings.ToLower(a.Key), strings.ToLower(b.Key))
+	})
+	return groups
+}
+
+// Heading returns the section heading of a group, e.g. "Assignee: alice (3 issues)".
+func (g *Grouping) Heading(group IssueGroup) string {
+	return fmt.Sprintf("%s: %s (%s)", g.Label, group.Key, pluralize(len(group.Issues), "issue", "issues"))
+}
+
+// GroupSetter is implemented by renderers that can split their output into sections.
+type GroupSetter interface {
+	SetGrouping(grouping *Grouping)
+}
+
+// groupSet holds the grouping of a renderer. It is embedded by renderers supporting sections.
+type groupSet struct {
+	grouping *Grouping
+}
+
+// SetGrouping splits the output into sections. A nil grouping disables sections.
+func (s *groupSet) SetGrouping(grouping *Grouping) {
+	s.grouping = grouping
+}
+
+// renderSections renders issues with body, once per section when a grouping is set.
+// heading formats the section title written before each section body.
+func (s *groupSet) renderSections(
+	issues []*gitlab.Issue,
+	context *Context,
+	writer io.Writer,
+	heading func(title string) string,
+	body func(issues []*gitlab.Issue) error,
+) error {
+	if s.grouping == nil {
+		return body(issues)
+	}
+	for i, group := range s.grouping.Group(issues, context) {
+		separator := ""
+		if i > 0 {
+			separator = "\n"
+		}
+		if _, err := fmt.Fprintf(writer, "%s%s", separator, heading(s.grouping.Heading(group))); err != nil {
+			return fmt.Errorf("failed to write section heading: %w", err)
+		}
+		if err := body(group.Issues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pluralize returns "<n> <singular>" or "<n> <plural>" depending on n.
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	for _, field := range []string{"project", "assignee", "milestone", "state", "Priority::"} {
+		if _, err := ParseGroupBy(field); err != nil {
+			t.Errorf("ParseGroupBy(%q) unexpected error = %v", field, err)
+		}
+	}
+	for _, field := range []string{"", "nope", "::"} {
+		if _, err := ParseGroupBy(field); err == nil {
+			t.Errorf("ParseGroupBy(%q) expected error but got none", field)
+		}
+	}
+}
+
+func TestGrouping_Group(t *testing.T) {
+	issues := []*gitlab.Issue{
+		{Title: "one", Assignees: []*gitlab.IssueAssignee{{Username: "bob"}}, Labels: gitlab.Labels{"priority::2"}},
+		{Title: "two"},
+		{Title: "three", Assignees: []*gitlab.IssueAssignee{{Username: "alice"}, {Username: "bob"}},
+			Labels: gitlab.Labels{"priority::1"}},
+	}
+
+	t.Run("assignee", func(t *testing.T) {
+		grouping, _ := ParseGroupBy("assignee")
+		groups := grouping.Group(issues, nil)
+		var keys []string
+		for _, g := range groups {
+			keys = append(keys, g.Key)
+		}
+		if strings.Join(keys, ",") != "alice,bob,(none)" {
+			t.Fatalf("group keys = %v, want alice,bob,(none)", keys)
+		}
+		if titles := issueTitles(groups[1].Issues); strings.Join(titles, ",") != "one,three" {
+			t.Errorf("bob's issues = %v, want one,three", titles)
+		}
+		if heading := grouping.Heading(groups[0]); heading != "Assignee: alice (1 issue)" {
+			t.Errorf("Heading() = %q", heading)
+		}
+	})
+
+	t.Run("scoped label", func(t *testing.T) {
+		grouping, _ := ParseGroupBy("priority::")
+		groups := grouping.Group(issues, nil)
+		if len(groups) != 3 || groups[0].Key != "1" || groups[1].Key != "2" || groups[2].Key != noGroupKey {
+			t.Fatalf("unexpected groups: %+v", groups)
+		}
+		if heading := grouping.Heading(groups[0]); heading != "priority: 1 (1 issue)" {
+			t.Errorf("Heading() = %q", heading)
+		}
+	})
+}
+
+// TestRenderers_Grouping verifies every sectioned renderer writes one heading per group.
+func TestRenderers_Grouping(t *testing.T) {
+	issues := createTestIssues()
+	grouping, err := ParseGroupBy("state")
+	if err != nil {
+		t.Fatalf("ParseGroupBy() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		renderer Renderer
+		expected []string
+	}{
+		{
+			name:     "plain",
+			renderer: NewPlainRenderer(true),
+			expected: []string{"State: closed (1 issue)\n", "State: opened (2 issues)\n"},
+		},
+		{
+			name:     "table",
+			renderer: NewTableRenderer(),
+			expected: []string{"State: closed (1 issue)\n", "State: opened (2 issues)\n"},
+		},
+		{
+			name:     "markdown",
+			renderer: NewMarkdownRenderer(),
+			expected: []string{"## State: closed (1 issue)\n\n| Title |", "## State: opened (2 issues)\n\n| Title |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setter, ok := tt.renderer.(GroupSetter)
+			if !ok {
+				t.Fatalf("%s renderer does not support grouping", tt.name)
+			}
+			setter.SetGrouping(grouping)
+
+			var buf bytes.Buffer
+			if err := tt.renderer.Render(issues, &buf); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			output := buf.String()
+			for _, exp := range tt.expected {
+				if !strings.Contains(output, exp) {
+					t.Errorf("output missing %q\nGot:\n%s", exp, output)
+				}
+			}
+			if strings.Index(output, "closed (") > strings.Index(output, "opened (") {
+				t.Errorf("sections not ordered by key\nGot:\n%s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package render
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+//go:embed templates/report.html.tmpl
+var htmlReportTemplate string
+
+// htmlTemplate is the parsed page layout of the HTML renderer.
+var htmlTemplate = template.Must(template.New("report").
+	Funcs(template.FuncMap{"pluralize": pluralize}).
+	Parse(htmlReportTemplate))
+
+// htmlReport is the data passed to the HTML page layout.
+type htmlReport struct {
+	Title       string
+	GeneratedAt string
+	Total       int
+	Columns     []Column
+	Sections    []htmlSection
+	ShowIssues  bool
+	Summary     [][2]string
+}
+
+// htmlSection is a table of the report, collapsible when it has a heading.
+type htmlSection struct {
+	Heading string
+	Rows    []htmlRow
+}
+
+// htmlRow is a table row.
+type htmlRow struct {
+	Cells []htmlCell
+}
+
+// htmlCell is a table cell. URL, State and Labels select a richer rendering than plain text.
+type htmlCell struct {
+	Value  string
+	URL    string
+	State  string
+	Labels []string
+}
+
+// HTMLRenderer renders issues as a single self-contained HTML page
+// with sortable and filterable tables.
+type HTMLRenderer struct {
+	columnSet
+	groupSet
+	summarySet
+	now func() time.Time
+}
+
+// NewHTMLRenderer creates a new HTMLRenderer.
+func NewHTMLRenderer() *HTMLRenderer {
+	return &HTMLRenderer{now: time.Now}
+}
+
+// Render renders issues as an HTML page.
+func (h *HTMLRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
+	return h.RenderWithContext(issues, nil, writer)
+}
+
+// RenderWithContext renders issues as an HTML page with contextual information.
+// Group reports get one collapsible section per project unless another grouping is set.
+func (h *HTMLRenderer) RenderWithContext(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	columns := h.resolve(context)
+	now := h.now()
+	report := htmlReport{
+		Title:       "GitLab Issues Report",
+		GeneratedAt: now.Format("2006-01-02 15:04 MST"),
+		Total:       len(issues),
+		Columns:     columns,
+		ShowIssues:  h.showIssues(),
+	}
+	if context != nil {
+		report.Title = "GitLab Issues Report - " + context.title()
+	}
+
+	grouping := h.grouping
+	if grouping == nil && context.spansProjects() {
+		byProject := groupingFields["project"]
+		grouping = &byProject
+	}
+	if h.summaryMode != SummaryNone {
+		report.Summary = NewSummary(issues, context, now).lines()
+	}
+
+	switch {
+	case len(issues) == 0, !report.ShowIssues:
+	case grouping == nil:
+		report.Sections = []htmlSection{{Rows: htmlRows(columns, issues, context)}}
+	default:
+		for _, group := range grouping.Group(issues, context) {
+			report.Sections = append(report.Sections, htmlSection{
+				Heading: grouping.Heading(group),
+				Rows:    htmlRows(columns, group.Issues, context),
+			})
+		}
+	}
+
+	if err := htmlTemplate.Execute(writer, report); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return nil
+}
+
+// htmlRows converts issues to table rows for the selected columns.
+func htmlRows(columns []Column, issues []*gitlab.Issue, context *Context) []htmlRow {
+	rows := make([]htmlRow, 0, len(issues))
+	for _, issue := range issues {
+		cells := make([]htmlCell, 0, len(columns))
+		for _, column := range columns {
+			cell := htmlCell{Value: column.Value(issue, context)}
+			switch column.Name {
+			case "title", "url", "iid":
+				cell.URL = issue.WebURL
+			case "state":
+				cell.State = issue.State
+			case "labels":
+				cell.Labels = issue.Labels
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, htmlRow{Cells: cells})
+	}
+	return rows
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestHTMLRenderer_Project(t *testing.T) {
+	issues := createTestIssues()
+	issues[0].WebURL = "https://gitlab.example.com/ns/p/-/issues/1"
+	issues[1].Title = "<script>alert(1)</script>"
+
+	var buf bytes.Buffer
+	if err := NewHTMLRenderer().RenderWithContext(issues, NewProjectContext("ns/p"), &buf); err != nil {
+		t.Fatalf("HTMLRenderer.RenderWithContext() error = %v", err)
+	}
+
+	output := buf.String()
+	expected := []string{
+		"<!DOCTYPE html>",
+		"<title>GitLab Issues Report - ns/p</title>",
+		"3 issues",
+		`<a href="https://gitlab.example.com/ns/p/-/issues/1">Fix authentication bug</a>`,
+		`<span class="badge state-opened">opened</span>`,
+		`<span class="chip">bug</span><span class="chip">backend</span>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		"function sortTable",
+		"function filterRows",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("output missing %q", exp)
+		}
+	}
+	for _, unexpected := range []string{"<script>alert(1)", "<details", "http://", "https://cdn"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("output unexpectedly contains %q", unexpected)
+		}
+	}
+}
+
+func TestHTMLRenderer_GroupSections(t *testing.T) {
+	context := NewGroupContext("my-group", map[int64]string{
+		100: "namespace/project-a",
+		200: "namespace/project-b",
+	})
+
+	var buf bytes.Buffer
+	if err := NewHTMLRenderer().RenderWithContext(createTestIssuesWithProjects(), context, &buf); err != nil {
+		t.Fatalf("HTMLRenderer.RenderWithContext() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, exp := range []string{
+		"GitLab Issues Report - Group: my-group",
+		"<summary>Project: namespace/project-a (2 issues)</summary>",
+		"<summary>Project: namespace/project-b (1 issue)</summary>",
+	} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("output missing %q", exp)
+		}
+	}
+	if strings.Count(output, "<details open>") != 2 {
+		t.Errorf("expected 2 collapsible sections\nGot:\n%s", output)
+	}
+}
+
+func TestHTMLRenderer_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewHTMLRenderer().Render([]*gitlab.Issue{}, &buf); err != nil {
+		t.Fatalf("HTMLRenderer.Render() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "No issues found.") {
+		t.Errorf("output missing empty message\nGot:\n%s", buf.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
@@ -22,8 +23,20 @@ type Renderer interface {
 	RenderWithContext(issues []*gitlab.Issue, context *Context, writer io.Writer) error
 }
 
+// StreamRenderer is implemented by renderers that can write issues incrementally,
+// one page at a time, instead of waiting for the complete list. Finish is called
+// once after the last page.
+type StreamRenderer interface {
+	Renderer
+	RenderPage(issues []*gitlab.Issue, context *Context, writer io.Writer) error
+	Finish(context *Context, writer io.Writer) error
+}
+
 // PlainRenderer renders issues in plain text format.
 type PlainRenderer struct {
+	columnSet
+	groupSet
+	summarySet
 	printHeader bool
 }
 
@@ -34,25 +47,7 @@ func NewPlainRenderer(printHeader bool) *PlainRenderer {
 
 // Render renders issues in plain text format.
 func (p *PlainRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
-	if p.printHeader {
-		headerFormat := "%-70s %10s %-12s %-12s %s\n"
-		if _, err := fmt.Fprintf(writer, headerFormat,
-			"Title", "State", "Created At", "Updated At", "Labels"); err != nil {
-			return fmt.Errorf("failed to write header: %w", err)
-		}
-	}
-	for idx := range issues {
-		title := truncateStr(issues[idx].Title, maxTitleLength)
-		state := issues[idx].State
-		createdAt := issues[idx].CreatedAt.Format("2006-01-02")
-		updatedAt := issues[idx].UpdatedAt.Format("2006-01-02")
-		labels := formatLabels(issues[idx].Labels)
-		rowFormat := "%-70s %10s %12s %12s %s\n"
-		if _, err := fmt.Fprintf(writer, rowFormat, title, state, createdAt, updatedAt, labels); err != nil {
-			return fmt.Errorf("failed to write issue: %w", err)
-		}
-	}
-	return nil
+	return p.renderReport(issues, nil, writer)
 }
 
 // RenderWithContext renders issues in plain text format with contextual information.
@@ -64,13 +59,25 @@ func (p *PlainRenderer) RenderWithContext(issues []*gitlab.Issue, context *Conte
 		}
 	}
 
-	// For group queries, add project column
-	if context != nil && context.Source == SourceTypeGroup {
-		return p.renderWithProjectColumn(issues, context, writer)
+	// Group queries get a project column through the default columns
+	return p.renderReport(issues, context, writer)
+}
+
+// renderReport renders the rows and the summary block, as selected by the summary mode.
+func (p *PlainRenderer) renderReport(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	if p.showIssues() {
+		if err := p.renderBody(issues, context, writer); err != nil {
+			return err
+		}
 	}
+	return p.writeTextSummary(issues, context, writer)
+}
 
-	// Fall back to regular rendering for project or no context
-	return p.Render(issues, writer)
+// renderBody renders the rows, split into sections when a grouping is set.
+func (p *PlainRenderer) renderBody(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	return p.renderSections(issues, context, writer,
+		func(title string) string { return title + "\n" },
+		func(section []*gitlab.Issue) error { return p.renderRows(section, context, writer) })
 }
 
 // writeContextHeader writes the context header line.
@@ -84,47 +91,66 @@ func (p *PlainRenderer) writeContextHeader(context *Context, writer io.Writer) e
 		if _, err := fmt.Fprintf(writer, "Group: %s\n\n", context.GroupPath); err != nil {
 			return fmt.Errorf("failed to write group header: %w", err)
 		}
+	case SourceTypeProjects, SourceTypeInstance:
+		if _, err := fmt.Fprintf(writer, "%s\n\n", context.title()); err != nil {
+			return fmt.Errorf("failed to write %s header: %w", context.Source, err)
+		}
 	}
 	return nil
 }
 
-// renderWithProjectColumn renders issues with a project column.
-func (p *PlainRenderer) renderWithProjectColumn(
-	issues []*gitlab.Issue,
-	context *Context,
-	writer io.Writer,
-) error {
+// renderRows renders the header and one line per issue using the selected columns.
+func (p *PlainRenderer) renderRows(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	columns := p.resolve(context)
+
+	// Titles are shortened when a project column has to fit on the same line
+	titleLength := maxTitleLength
+	if hasColumn(columns, "project") {
+		titleLength = maxTitleLengthWithProject
+	}
+
 	if p.printHeader {
-		headerFormat := "%-40s %-30s %10s %-12s %-12s %s\n"
-		if _, err := fmt.Fprintf(writer, headerFormat,
-			"Project", "Title", "State", "Created At", "Updated At", "Labels"); err != nil {
+		if _, err := io.WriteString(writer, formatPlainLine(columns, headers(columns), titleLength, true)); err != nil {
 			return fmt.Errorf("failed to write header: %w", err)
 		}
 	}
-
 	for _, issue := range issues {
-		projectPath := context.ProjectMap[issue.ProjectID]
-		if projectPath == "" {
-			projectPath = fmt.Sprintf("ID:%d", issue.ProjectID)
-		}
-
-		title := truncateStr(issue.Title, maxTitleLengthWithProject)
-		rowFormat := "%-40s %-30s %10s %12s %12s %s\n"
-		if _, err := fmt.Fprintf(writer, rowFormat,
-			projectPath,
-			title,
-			issue.State,
-			issue.CreatedAt.Format("2006-01-02"),
-			issue.UpdatedAt.Format("2006-01-02"),
-			formatLabels(issue.Labels)); err != nil {
+		line := formatPlainLine(columns, row(columns, issue, context), titleLength, false)
+		if _, err := io.WriteString(writer, line); err != nil {
 			return fmt.Errorf("failed to write issue: %w", err)
 		}
 	}
 	return nil
 }
 
+// formatPlainLine pads values to their column width. The last column is not padded.
+// The header line is padded like the values, except the headers left-aligned over
+// right-aligned values.
+func formatPlainLine(columns []Column, values []string, titleLength int, header bool) string {
+	parts := make([]string, 0, len(columns))
+	for i, column := range columns {
+		value, width := values[i], column.width
+		if column.Name == "title" {
+			value, width = truncateStr(value, titleLength), titleLength
+		}
+		switch {
+		case i == len(columns)-1:
+			parts = append(parts, value)
+		case column.alignRight && !(header && column.headerLeft):
+			parts = append(parts, fmt.Sprintf("%*s", width, value))
+		default:
+			parts = append(parts, fmt.Sprintf("%-*s", width, value))
+		}
+	}
+	return strings.Join(parts, " ") + "\n"
+}
+
 // TableRenderer renders issues in table format.
-type TableRenderer struct{}
+type TableRenderer struct {
+	columnSet
+	groupSet
+	summarySet
+}
 
 // NewTableRenderer creates a new TableRenderer.
 func NewTableRenderer() *TableRenderer {
@@ -133,25 +159,7 @@ func NewTableRenderer() *TableRenderer {
 
 // Render renders issues in table format.
 func (t *TableRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
-	table := tablewriter.NewWriter(writer)
-	table.Header([]string{"Title", "State", "CreatedAt", "UpdatedAt", "Labels"})
-
-	for _, v := range issues {
-		row := []string{
-			v.Title,
-			v.State,
-			v.CreatedAt.Format("2006-01-02"),
-			v.UpdatedAt.Format("2006-01-02"),
-			formatLabels(v.Labels),
-		}
-		if err := table.Append(row); err != nil {
-			return fmt.Errorf("error appending table row: %w", err)
-		}
-	}
-	if err := table.Render(); err != nil {
-		return fmt.Errorf("error rendering table: %w", err)
-	}
-	return nil
+	return t.renderReport(issues, nil, writer)
 }
 
 // RenderWithContext renders issues in table format with contextual information.
@@ -163,60 +171,34 @@ func (t *TableRenderer) RenderWithContext(issues []*gitlab.Issue, context *Conte
 		}
 	}
 
-	table := tablewriter.NewWriter(writer)
-
-	// Adjust headers and data based on context
-	if context != nil && context.Source == SourceTypeGroup {
-		return t.renderGroupTable(table, issues, context)
-	}
-
-	return t.renderRegularTable(table, issues)
+	return t.renderReport(issues, context, writer)
 }
 
-// renderGroupTable renders the table with project column for group context.
-func (t *TableRenderer) renderGroupTable(
-	table *tablewriter.Table,
-	issues []*gitlab.Issue,
-	context *Context,
-) error {
-	table.Header([]string{"Project", "Title", "State", "CreatedAt", "UpdatedAt", "Labels"})
-
-	for _, issue := range issues {
-		projectPath := context.ProjectMap[issue.ProjectID]
-		if projectPath == "" {
-			projectPath = fmt.Sprintf("ID:%d", issue.ProjectID)
-		}
-		row := []string{
-			projectPath,
-			issue.Title,
-			issue.State,
-			issue.CreatedAt.Format("2006-01-02"),
-			issue.UpdatedAt.Format("2006-01-02"),
-			formatLabels(issue.Labels),
-		}
-		if err := table.Append(row); err != nil {
-			return fmt.Errorf("error appending table row: %w", err)
+// renderReport renders the tables and the summary block, as selected by the summary mode.
+func (t *TableRenderer) renderReport(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	if t.showIssues() {
+		if err := t.renderBody(issues, context, writer); err != nil {
+			return err
 		}
 	}
+	return t.writeTextSummary(issues, context, writer)
+}
 
-	if err := table.Render(); err != nil {
-		return fmt.Errorf("error rendering table: %w", err)
-	}
-	return nil
+// renderBody renders one table, or one table per section when a grouping is set.
+func (t *TableRenderer) renderBody(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	return t.renderSections(issues, context, writer,
+		func(title string) string { return title + "\n" },
+		func(section []*gitlab.Issue) error { return t.renderTable(section, context, writer) })
 }
 
-// renderRegularTable renders the table without project column.
-func (t *TableRenderer) renderRegularTable(table *tablewriter.Table, issues []*gitlab.Issue) error {
-	table.Header([]string{"Title", "State", "CreatedAt", "UpdatedAt", "Labels"})
+// renderTable renders the table using the selected columns.
+func (t *TableRenderer) renderTable(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	columns := t.resolve(context)
+	table := tablewriter.NewWriter(writer)
+	table.Header(tableHeaders(columns))
+
 	for _, issue := range issues {
-		row := []string{
-			issue.Title,
-			issue.State,
-			issue.CreatedAt.Format("2006-01-02"),
-			issue.UpdatedAt.Format("2006-01-02"),
-			formatLabels(issue.Labels),
-		}
-		if err := table.Append(row); err != nil {
+		if err := table.Append(row(columns, issue, context)); err != nil {
 			return fmt.Errorf("error appending table row: %w", err)
 		}
 	}
@@ -238,12 +220,20 @@ func (t *TableRenderer) writeContextHeader(context *Context, writer io.Writer) e
 		if _, err := fmt.Fprintf(writer, "Group: %s\n\n", context.GroupPath); err != nil {
 			return fmt.Errorf("failed to write group header: %w", err)
 		}
+	case SourceTypeProjects, SourceTypeInstance:
+		if _, err := fmt.Fprintf(writer, "%s\n\n", context.title()); err != nil {
+			return fmt.Errorf("failed to write %s header: %w", context.Source, err)
+		}
 	}
 	return nil
 }
 
 // MarkdownRenderer renders issues in markdown format.
-type MarkdownRenderer struct{}
+type MarkdownRenderer struct {
+	columnSet
+	groupSet
+	summarySet
+}
 
 // NewMarkdownRenderer creates a new MarkdownRenderer.
 func NewMarkdownRenderer() *MarkdownRenderer {
@@ -252,40 +242,7 @@ func NewMarkdownRenderer() *MarkdownRenderer {
 
 // Render renders issues in markdown format.
 func (m *MarkdownRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
-	if len(issues) == 0 {
-		if _, err := fmt.Fprintf(writer, "# GitLab Issues Report\n\nNo issues found.\n"); err != nil {
-			return fmt.Errorf("failed to write empty message: %w", err)
-		}
-		return nil
-	}
-
-	if _, err := fmt.Fprintf(writer, "# GitLab Issues Report\n\n"); err != nil {
-		return fmt.Errorf("failed to write title: %w", err)
-	}
-	if _, err := fmt.Fprintf(writer, "| Title | State | Created At | Updated At | Labels |\n"); err != nil {
-		return fmt.Errorf("failed to write table header: %w", err)
-	}
-	if _, err := fmt.Fprintf(writer, "|-------|-------|------------|------------|--------|\n"); err != nil {
-		return fmt.Errorf("failed to write table separator: %w", err)
-	}
-
-	for _, issue := range issues {
-		// Escape markdown special characters in title
-		title := strings.ReplaceAll(issue.Title, "|", "\\|")
-		title = strings.ReplaceAll(title, "\n", " ")
-		title = strings.ReplaceAll(title, "\r", " ")
-
-		createdAt := issue.CreatedAt.Format("2006-01-02")
-		updatedAt := issue.UpdatedAt.Format("2006-01-02")
-		labels := strings.ReplaceAll(formatLabels(issue.Labels), "|", "\\|")
-
-		if _, err := fmt.Fprintf(writer, "| %s | %s | %s | %s | %s |\n",
-			title, issue.State, createdAt, updatedAt, labels); err != nil {
-			return fmt.Errorf("failed to write issue row: %w", err)
-		}
-	}
-
-	return nil
+	return m.renderReport("# GitLab Issues Report\n\n", issues, nil, writer)
 }
 
 // RenderWithContext renders issues in markdown format with contextual information.
@@ -293,97 +250,72 @@ func (m *MarkdownRenderer) RenderWithContext(issues []*gitlab.Issue, context *Co
 	// Generate title with context
 	title := "# GitLab Issues Report\n\n"
 	if context != nil {
-		if context.Source == SourceTypeProject {
-			title = fmt.Sprintf("# GitLab Issues Report - %s\n\n", context.ProjectPath)
-		} else {
-			title = fmt.Sprintf("# GitLab Issues Report - Group: %s\n\n", context.GroupPath)
-		}
+		title = fmt.Sprintf("# GitLab Issues Report - %s\n\n", context.title())
 	}
 
-	if len(issues) == 0 {
-		if _, err := fmt.Fprintf(writer, "%sNo issues found.\n", title); err != nil {
-			return fmt.Errorf("failed to write empty message: %w", err)
-		}
-		return nil
-	}
+	return m.renderReport(title, issues, context, writer)
+}
 
+// renderReport writes the title followed by the issues table, or a message when empty,
+// and the summary section when enabled.
+func (m *MarkdownRenderer) renderReport(
+	title string,
+	issues []*gitlab.Issue,
+	context *Context,
+	writer io.Writer,
+) error {
 	if _, err := fmt.Fprintf(writer, "%s", title); err != nil {
 		return fmt.Errorf("failed to write title: %w", err)
 	}
 
-	// For group context, add project column
-	if context != nil && context.Source == SourceTypeGroup {
-		return m.renderWithProjectColumn(issues, context, writer)
+	if m.showIssues() {
+		if err := m.renderBody(issues, context, writer); err != nil {
+			return err
+		}
 	}
 
-	// Regular rendering without project column
-	return m.renderTable(issues, writer)
+	return m.writeSummaryWith(writer, func() error {
+		return NewSummary(issues, context, time.Now()).WriteMarkdown(writer)
+	})
 }
 
-// renderTable renders the markdown table for issues.
-func (m *MarkdownRenderer) renderTable(issues []*gitlab.Issue, writer io.Writer) error {
-	if _, err := fmt.Fprintf(writer, "| Title | State | Created At | Updated At | Labels |\n"); err != nil {
-		return fmt.Errorf("failed to write table header: %w", err)
-	}
-	if _, err := fmt.Fprintf(writer, "|-------|-------|------------|------------|--------|\n"); err != nil {
-		return fmt.Errorf("failed to write table separator: %w", err)
+// renderBody writes the issue tables, or a message when there are no issues.
+func (m *MarkdownRenderer) renderBody(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	if len(issues) == 0 {
+		if _, err := fmt.Fprintln(writer, "No issues found."); err != nil {
+			return fmt.Errorf("failed to write empty message: %w", err)
+		}
+		return nil
 	}
 
-	for _, issue := range issues {
-		title := strings.ReplaceAll(issue.Title, "|", "\\|")
-		title = strings.ReplaceAll(title, "\n", " ")
-		title = strings.ReplaceAll(title, "\r", " ")
+	return m.renderSections(issues, context, writer,
+		func(title string) string { return "## " + escapeMarkdownCell(title) + "\n\n" },
+		func(section []*gitlab.Issue) error { return m.renderTable(section, context, writer) })
+}
 
-		createdAt := issue.CreatedAt.Format("2006-01-02")
-		updatedAt := issue.UpdatedAt.Format("2006-01-02")
-		labels := strings.ReplaceAll(formatLabels(issue.Labels), "|", "\\|")
+// renderTable renders the markdown table for issues using the selected columns.
+func (m *MarkdownRenderer) renderTable(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	columns := m.resolve(context)
+	headerCells := headers(columns)
 
-		if _, err := fmt.Fprintf(writer, "| %s | %s | %s | %s | %s |\n",
-			title, issue.State, createdAt, updatedAt, labels); err != nil {
-			return fmt.Errorf("failed to write issue row: %w", err)
-		}
+	separators := make([]string, 0, len(headerCells))
+	for _, h := range headerCells {
+		separators = append(separators, strings.Repeat("-", len(h)+2))
 	}
 
-	return nil
-}
-
-// renderWithProjectColumn renders the markdown table with a project column.
-func (m *MarkdownRenderer) renderWithProjectColumn(
-	issues []*gitlab.Issue,
-	context *Context,
-	writer io.Writer,
-) error {
-	if _, err := fmt.Fprintf(writer, "| Project | Title | State | Created At | Updated At | Labels |\n"); err != nil {
+	if _, err := fmt.Fprintf(writer, "| %s |\n", strings.Join(headerCells, " | ")); err != nil {
 		return fmt.Errorf("failed to write table header: %w", err)
 	}
-	if _, err := fmt.Fprintf(writer, "|---------|-------|-------|------------|------------|--------|\n"); err != nil {
+	if _, err := fmt.Fprintf(writer, "|%s|\n", strings.Join(separators, "|")); err != nil {
 		return fmt.Errorf("failed to write table separator: %w", err)
 	}
 
 	for _, issue := range issues {
-		projectPath := context.ProjectMap[issue.ProjectID]
-		if projectPath == "" {
-			projectPath = fmt.Sprintf("ID:%d", issue.ProjectID)
+		cells := row(columns, issue, context)
+		for i := range cells {
+			cells[i] = escapeMarkdownCell(cells[i])
 		}
-
-		title := strings.ReplaceAll(issue.Title, "|", "\\|")
-		title = strings.ReplaceAll(title, "\n", " ")
-		title = strings.ReplaceAll(title, "\r", " ")
-
-		createdAt := issue.CreatedAt.Format("2006-01-02")
-		updatedAt := issue.UpdatedAt.Format("2006-01-02")
-		labels := strings.ReplaceAll(formatLabels(issue.Labels), "|", "\\|")
-
-		if _, err := fmt.Fprintf(
-			writer,
-			"| %s | %s | %s | %s | %s | %s |\n",
-			projectPath,
-			title,
-			issue.State,
-			createdAt,
-			updatedAt,
-			labels,
-		); err != nil {
+		if _, err := fmt.Fprintf(writer, "| %s |\n", strings.Join(cells, " | ")); err != nil {
 			return fmt.Errorf("failed to write issue row: %w", err)
 		}
 	}
@@ -391,6 +323,13 @@ func (m *MarkdownRenderer) renderWithProjectColumn(
 	return nil
 }
 
+// escapeMarkdownCell escapes pipes and flattens newlines so a value fits in a table cell.
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\n", " ")
+	return strings.ReplaceAll(value, "\r", " ")
+}
+
 func truncateStr(str string, length int) string {
 	if len(str) > length && length > 0 {
 		return str[:length]
@@ -262,6 +262,11 @@ func TestRendererInterface(t *testing.T) {
 	var _ Renderer = NewMarkdownRenderer()
 	var _ Renderer = NewPlainRenderer(true)
 	var _ Renderer = NewTableRenderer()
+	var _ Renderer = NewJSONRenderer()
+	var _ StreamRenderer = NewNDJSONRenderer()
+	var _ Renderer = NewCSVRenderer()
+	var _ Renderer = &TemplateRenderer{}
+	var _ Renderer = NewHTMLRenderer()
 }
 
 // TestRenderers_Labels verifies that issue labels appear in the output of every renderer.
@@ -552,4 +557,46 @@ func BenchmarkTableRenderer(b *testing.B) {
 		var buf bytes.Buffer
 		_ = renderer.Render(issues, &buf)
 	}
-}
\ No newline at end of file
+}
+
+func TestMarkdownRenderer_RenderWithContext_Projects(t *testing.T) {
+	issues := createTestIssuesWithProjects()
+	context := NewProjectsContext([]string{"namespace/project-a", "other/project-b"}, map[int64]string{
+		100: "namespace/project-a",
+		200: "other/project-b",
+	})
+
+	var buf bytes.Buffer
+	if err := NewMarkdownRenderer().RenderWithContext(issues, context, &buf); err != nil {
+		t.Fatalf("MarkdownRenderer.RenderWithContext() error = %v", err)
+	}
+
+	output := buf.String()
+	expected := []string{
+		"# GitLab Issues Report - Projects: namespace/project-a, other/project-b",
+		"| Project | Title | State | Created At | Updated At |",
+		"| other/project-b |",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Output missing expected string: %q\nGot:\n%s", exp, output)
+		}
+	}
+}
+
+func TestPlainRenderer_RenderWithContext_Instance(t *testing.T) {
+	issues := createTestIssuesWithProjects()
+	context := NewInstanceContext("assigned_to_me", map[int64]string{100: "namespace/project-a"})
+
+	var buf bytes.Buffer
+	if err := NewPlainRenderer(true).RenderWithContext(issues, context, &buf); err != nil {
+		t.Fatalf("PlainRenderer.RenderWithContext() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, exp := range []string{"Scope: assigned_to_me", "Project", "namespace/project-a"} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Output missing expected string: %q\nGot:\n%s", exp, output)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// JSONSchemaVersion is the version of the JSON report schema.
+// It must be bumped whenever a field is removed or its meaning changes.
+const JSONSchemaVersion = "1"
+
+// jsonReport is the envelope written by the JSON renderer.
+type jsonReport struct {
+	SchemaVersion string            `json:"schema_version"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+	Query         map[string]string `json:"query"`
+	Source        SourceType        `json:"source,omitempty"`
+	ProjectPath   string            `json:"project_path,omitempty"`
+	GroupPath     string            `json:"group_path,omitempty"`
+	ProjectPaths  []string          `json:"project_paths,omitempty"`
+	Scope         string            `json:"scope,omitempty"`
+	Count         int               `json:"count"`
+	Issues        []jsonIssue       `json:"issues,omitzero"`
+	Summary       *Summary          `json:"summary,omitempty"`
+}
+
+// jsonIssue is the representation of a single issue in the JSON schema.
+type jsonIssue struct {
+	ID          int64          `json:"id"`
+	IID         int64          `json:"iid"`
+	ProjectID   int64          `json:"project_id"`
+	ProjectPath string         `json:"project_path,omitempty"`
+	Title       string         `json:"title"`
+	State       string         `json:"state"`
+	WebURL      string         `json:"web_url"`
+	Labels      []string       `json:"labels"`
+	Author      *jsonUser      `json:"author"`
+	Assignees   []jsonUser     `json:"assignees"`
+	Milestone   *jsonMilestone `json:"milestone"`
+	DueDate     string         `json:"due_date,omitempty"`
+	Weight      int64          `json:"weight"`
+	CreatedAt   *time.Time     `json:"created_at"`
+	UpdatedAt   *time.Time     `json:"updated_at"`
+	ClosedAt    *time.Time     `json:"closed_at"`
+}
+
+// jsonUser is the representation of an issue author or assignee.
+type jsonUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// jsonMilestone is the representation of an issue milestone.
+type jsonMilestone struct {
+	ID    int64  `json:"id"`
+	IID   int64  `json:"iid"`
+	Title string `json:"title"`
+}
+
+// JSONRenderer renders issues as a versioned JSON document.
+type JSONRenderer struct {
+	summarySet
+	now func() time.Time
+}
+
+// NewJSONRenderer creates a new JSONRenderer.
+func NewJSONRenderer() *JSONRenderer {
+	return &JSONRenderer{now: time.Now}
+}
+
+// Render renders issues as a JSON document.
+func (j *JSONRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
+	return j.RenderWithContext(issues, nil, writer)
+}
+
+// RenderWithContext renders issues as a JSON document with contextual information.
+func (j *JSONRenderer) RenderWithContext(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	now := j.now()
+	report := jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		GeneratedAt:   now.UTC(),
+		Query:         map[string]string{},
+		Count:         len(issues),
+	}
+	if context != nil {
+		report.Source = context.Source
+		report.ProjectPath = context.ProjectPath
+		report.GroupPath = context.GroupPath
+		report.ProjectPaths = context.Projects
+		report.Scope = context.Scope
+		if context.Query != nil {
+			report.Query = context.Query
+		}
+	}
+	// Issues are omitted in summary-only mode, but an empty list is kept as []
+	if j.showIssues() {
+		report.Issues = make([]jsonIssue, 0, len(issues))
+		for _, issue := range issues {
+			report.Issues = append(report.Issues, newJSONIssue(issue, context))
+		}
+	}
+	if j.summaryMode != SummaryNone {
+		report.Summary = NewSummary(issues, context, now)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	return nil
+}
+
+// newJSONIssue converts a GitLab issue to its JSON schema representation.
+func newJSONIssue(issue *gitlab.Issue, context *Context) jsonIssue {
+	out := jsonIssue{
+		ID:          issue.ID,
+		IID:         issue.IID,
+		ProjectID:   issue.ProjectID,
+		ProjectPath: context.projectPathFor(issue.ProjectID),
+		Title:       issue.Title,
+		State:       issue.State,
+		WebURL:      issue.WebURL,
+		Labels:      []string(issue.Labels),
+		Assignees:   make([]jsonUser, 0, len(issue.Assignees)),
+		Weight:      issue.Weight,
+		CreatedAt:   issue.CreatedAt,
+		UpdatedAt:   issue.UpdatedAt,
+		ClosedAt:    issue.ClosedAt,
+	}
+	if out.Labels == nil {
+		out.Labels = []string{}
+	}
+	if issue.Author != nil {
+		out.Author = &jsonUser{ID: issue.Author.ID, Username: issue.Author.Username, Name: issue.Author.Name}
+	}
+	for _, assignee := range issue.Assignees {
+		if assignee == nil {
+			continue
+		}
+		out.Assignees = append(out.Assignees, jsonUser{
+			ID:       assignee.ID,
+			Username: assignee.Username,
+			Name:     assignee.Name,
+		})
+	}
+	if issue.Milestone != nil {
+		out.Milestone = &jsonMilestone{
+			ID:    issue.Milestone.ID,
+			IID:   issue.Milestone.IID,
+			Title: issue.Milestone.Title,
+		}
+	}
+	if issue.DueDate != nil {
+		out.DueDate = issue.DueDate.String()
+	}
+	return out
+}
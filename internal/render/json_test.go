@@ -0,0 +1,116 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// decodeJSONReport renders issues with the JSON renderer and decodes the output.
+func decodeJSONReport(t *testing.T, issues []*gitlab.Issue, context *Context) map[string]any {
+	t.Helper()
+	renderer := NewJSONRenderer()
+	renderer.now = func() time.Time { return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) }
+
+	var buf bytes.Buffer
+	if err := renderer.RenderWithContext(issues, context, &buf); err != nil {
+		t.Fatalf("JSONRenderer.RenderWithContext() error = %v", err)
+	}
+
+	var report map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("output is not valid JSON: %v\nGot:\n%s", err, buf.String())
+	}
+	return report
+}
+
+func TestJSONRenderer_Envelope(t *testing.T) {
+	context := NewProjectContext("my-namespace/my-project")
+	context.Query = map[string]string{"state": "opened"}
+
+	report := decodeJSONReport(t, createTestIssues(), context)
+
+	if report["schema_version"] != JSONSchemaVersion {
+		t.Errorf("schema_version = %v, want %q", report["schema_version"], JSONSchemaVersion)
+	}
+	if report["generated_at"] != "2024-01-15T10:00:00Z" {
+		t.Errorf("generated_at = %v", report["generated_at"])
+	}
+	if report["source"] != "project" {
+		t.Errorf("source = %v, want project", report["source"])
+	}
+	if report["project_path"] != "my-namespace/my-project" {
+		t.Errorf("project_path = %v", report["project_path"])
+	}
+	if query, _ := report["query"].(map[string]any); query["state"] != "opened" {
+		t.Errorf("query = %v, want state=opened", report["query"])
+	}
+	if report["count"] != float64(3) {
+		t.Errorf("count = %v, want 3", report["count"])
+	}
+	if issues, _ := report["issues"].([]any); len(issues) != 3 {
+		t.Errorf("len(issues) = %d, want 3", len(issues))
+	}
+}
+
+func TestJSONRenderer_EmptyIssues(t *testing.T) {
+	report := decodeJSONReport(t, []*gitlab.Issue{}, nil)
+
+	issues, ok := report["issues"].([]any)
+	if !ok || len(issues) != 0 {
+		t.Errorf("issues = %v, want empty array", report["issues"])
+	}
+	if _, ok := report["query"].(map[string]any); !ok {
+		t.Errorf("query = %v, want empty object", report["query"])
+	}
+}
+
+func TestJSONRenderer_IssueFields(t *testing.T) {
+	due := gitlab.ISOTime(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	issues := createTestIssuesWithProjects()
+	issues[0].IID = 42
+	issues[0].WebURL = "https://gitlab.example.com/namespace/project-a/-/issues/42"
+	issues[0].Author = &gitlab.IssueAuthor{ID: 7, Username: "alice", Name: "Alice"}
+	issues[0].Assignees = []*gitlab.IssueAssignee{{ID: 8, Username: "bob", Name: "Bob"}}
+	issues[0].Milestone = &gitlab.Milestone{ID: 3, IID: 1, Title: "v1.0"}
+	issues[0].DueDate = &due
+
+	context := NewGroupContext("my-group", map[int64]string{
+		100: "namespace/project-a",
+		200: "namespace/project-b",
+	})
+	report := decodeJSONReport(t, issues, context)
+
+	first := report["issues"].([]any)[0].(map[string]any)
+	expected := map[string]any{
+		"iid":          float64(42),
+		"web_url":      "https://gitlab.example.com/namespace/project-a/-/issues/42",
+		"project_path": "namespace/project-a",
+		"due_date":     "2024-02-01",
+	}
+	for key, want := range expected {
+		if first[key] != want {
+			t.Errorf("issue[%q] = %v, want %v", key, first[key], want)
+		}
+	}
+	if author, _ := first["author"].(map[string]any); author["username"] != "alice" {
+		t.Errorf("author = %v, want alice", first["author"])
+	}
+	if assignees, _ := first["assignees"].([]any); len(assignees) != 1 {
+		t.Errorf("assignees = %v, want one assignee", first["assignees"])
+	}
+	if milestone, _ := first["milestone"].(map[string]any); milestone["title"] != "v1.0" {
+		t.Errorf("milestone = %v, want v1.0", first["milestone"])
+	}
+
+	second := report["issues"].([]any)[1].(map[string]any)
+	if second["project_path"] != "namespace/project-b" {
+		t.Errorf("second issue project_path = %v", second["project_path"])
+	}
+	if second["author"] != nil || second["milestone"] != nil {
+		t.Errorf("missing author/milestone should be null, got %v / %v", second["author"], second["milestone"])
+	}
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// NDJSONRenderer renders issues as newline-delimited JSON, one issue per line.
+// Each line uses the same issue schema as the JSON renderer. When the summary is
+// enabled, a final {"summary": ...} line is written by Finish.
+type NDJSONRenderer struct {
+	summarySet
+	summary *summaryBuilder
+}
+
+// ndjsonSummary is the last line written when the summary is enabled.
+type ndjsonSummary struct {
+	Summary *Summary `json:"summary"`
+}
+
+// NewNDJSONRenderer creates a new NDJSONRenderer.
+func NewNDJSONRenderer() *NDJSONRenderer {
+	return &NDJSONRenderer{}
+}
+
+// Render renders issues as newline-delimited JSON.
+func (n *NDJSONRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
+	return n.RenderPage(issues, nil, writer)
+}
+
+// RenderWithContext renders issues as newline-delimited JSON with contextual information.
+func (n *NDJSONRenderer) RenderWithContext(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	if err := n.RenderPage(issues, context, writer); err != nil {
+		return err
+	}
+	return n.Finish(context, writer)
+}
+
+// RenderPage writes one JSON line per issue. It can be called repeatedly as pages arrive.
+func (n *NDJSONRenderer) RenderPage(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	if n.summaryMode != SummaryNone {
+		if n.summary == nil {
+			n.summary = newSummaryBuilder(context, time.Now())
+		}
+		n.summary.add(issues)
+	}
+	if !n.showIssues() {
+		return nil
+	}
+
+	encoder := json.NewEncoder(writer)
+	for _, issue := range issues {
+		if err := encoder.Encode(newJSONIssue(issue, context)); err != nil {
+			return fmt.Errorf("failed to write issue line: %w", err)
+		}
+	}
+	return nil
+}
+
+// Finish writes the summary line when the summary is enabled, covering every
+// page rendered since the previous call.
+func (n *NDJSONRenderer) Finish(context *Context, writer io.Writer) error {
+	if n.summaryMode == SummaryNone {
+		return nil
+	}
+	builder := n.summary
+	if builder == nil {
+		builder = newSummaryBuilder(context, time.Now())
+	}
+	n.summary = nil
+	if err := json.NewEncoder(writer).Encode(ndjsonSummary{Summary: builder.build()}); err != nil {
+		return fmt.Errorf("failed to write summary line: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestNDJSONRenderer_OneLinePerIssue(t *testing.T) {
+	renderer := NewNDJSONRenderer()
+	var buf bytes.Buffer
+
+	if err := renderer.Render(createTestIssues(), &buf); err != nil {
+		t.Fatalf("NDJSONRenderer.Render() error = %v", err)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var issue map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v\nGot: %s", lines+1, err, scanner.Text())
+		}
+		if _, ok := issue["title"]; !ok {
+			t.Errorf("line %d missing title: %s", lines+1, scanner.Text())
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("got %d lines, want 3", lines)
+	}
+}
+
+func TestNDJSONRenderer_RenderPageAppends(t *testing.T) {
+	issues := createTestIssuesWithProjects()
+	context := NewGroupContext("my-group", map[int64]string{100: "namespace/project-a"})
+
+	renderer := NewNDJSONRenderer()
+	var buf bytes.Buffer
+
+	if err := renderer.RenderPage(issues[:1], context, &buf); err != nil {
+		t.Fatalf("RenderPage() error = %v", err)
+	}
+	// Paths discovered while paginating are picked up by later pages.
+	context.ProjectMap[200] = "namespace/project-b"
+	if err := renderer.RenderPage(issues[1:2], context, &buf); err != nil {
+		t.Fatalf("RenderPage() error = %v", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2\nGot:\n%s", len(lines), buf.String())
+	}
+	for i, want := range []string{"namespace/project-a", "namespace/project-b"} {
+		var issue map[string]any
+		if err := json.Unmarshal(lines[i], &issue); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i+1, err)
+		}
+		if issue["project_path"] != want {
+			t.Errorf("line %d project_path = %v, want %q", i+1, issue["project_path"], want)
+		}
+	}
+}
+
+func TestNDJSONRenderer_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewNDJSONRenderer().Render([]*gitlab.Issue{}, &buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,178 @@
+package render
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+var errUnknownSortKey = errors.New("unknown sort key")
+
+// priorityLabelPrefix is the scoped label prefix used by the "priority" sort key.
+const priorityLabelPrefix = "priority::"
+
+// SortKey is one key of a multi-key sort.
+type SortKey struct {
+	Field      string // One of SortFields
+	Descending bool   // Sort from highest to lowest
+}
+
+// issueComparators compare two issues on a single field, in ascending order.
+// Missing values (no due date, no priority label...) compare as greater than any value.
+var issueComparators = map[string]func(a, b *gitlab.Issue, context *Context) int{
+	"created": func(a, b *gitlab.Issue, _ *Context) int { return compareTime(a.CreatedAt, b.CreatedAt) },
+	"updated": func(a, b *gitlab.Issue, _ *Context) int { return compareTime(a.UpdatedAt, b.UpdatedAt) },
+	"closed":  func(a, b *gitlab.Issue, _ *Context) int { return compareTime(a.ClosedAt, b.ClosedAt) },
+	"due":     func(a, b *gitlab.Issue, _ *Context) int { return compareTime(dueTime(a), dueTime(b)) },
+	"priority": func(a, b *gitlab.Issue, _ *Context) int {
+		return compareLabelValue(scopedLabelValue(a.Labels, priorityLabelPrefix),
+			scopedLabelValue(b.Labels, priorityLabelPrefix))
+	},
+	"weight": func(a, b *gitlab.Issue, _ *Context) int { return cmp.Compare(a.Weight, b.Weight) },
+	"title": func(a, b *gitlab.Issue, _ *Context) int {
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	},
+	"project": func(a, b *gitlab.Issue, context *Context) int {
+		return strings.Compare(projectValue(a, context), projectValue(b, context))
+	},
+	"iid":   func(a, b *gitlab.Issue, _ *Context) int { return cmp.Compare(a.IID, b.IID) },
+	"state": func(a, b *gitlab.Issue, _ *Context) int { return strings.Compare(a.State, b.State) },
+}
+
+// issueMissing reports, for the fields that can be unset, whether an issue has no value.
+// Issues without a value sort last whatever the direction.
+var issueMissing = map[string]func(issue *gitlab.Issue) bool{
+	"created":  func(issue *gitlab.Issue) bool { return issue.CreatedAt == nil },
+	"updated":  func(issue *gitlab.Issue) bool { return issue.UpdatedAt == nil },
+	"closed":   func(issue *gitlab.Issue) bool { return issue.ClosedAt == nil },
+	"due":      func(issue *gitlab.Issue) bool { return issue.DueDate == nil },
+	"priority": func(issue *gitlab.Issue) bool { return scopedLabelValue(issue.Labels, priorityLabelPrefix) == "" },
+}
+
+// SortFields returns the names of the fields issues can be sorted by.
+func SortFields() []string {
+	fields := make([]string, 0, len(issueComparators))
+	for field := range issueComparators {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// ParseSortKeys parses sort keys of the form "field" or "field:asc|desc".
+func ParseSortKeys(specs []string) ([]SortKey, error) {
+	keys := make([]SortKey, 0, len(specs))
+	for _, spec := range specs {
+		field, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+		if _, ok := issueComparators[field]; !ok {
+			return nil, fmt.Errorf("%w: %q (available: %s)", errUnknownSortKey, spec,
+				strings.Join(SortFields(), ", "))
+		}
+		key := SortKey{Field: field}
+		switch direction {
+		case "", "asc":
+		case "desc":
+			key.Descending = true
+		default:
+			return nil, fmt.Errorf("%w: %q (direction must be asc or desc)", errUnknownSortKey, spec)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SortIssues sorts issues in place by the given keys. The sort is stable, so issues
+// that compare equal on every key keep the order returned by the GitLab API. Issues
+// missing the value of a key sort last, for descending keys too.
+func SortIssues(issues []*gitlab.Issue, keys []SortKey, context *Context) {
+	if len(keys) == 0 {
+		return
+	}
+	slices.SortStableFunc(issues, func(a, b *gitlab.Issue) int {
+		for _, key := range keys {
+			if c := compareIssues(a, b, key, context); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// compareIssues compares two issues on one key. The direction only applies when both
+// issues have a value, an issue without one sorting after the other.
+func compareIssues(a, b *gitlab.Issue, key SortKey, context *Context) int {
+	if missing, ok := issueMissing[key.Field]; ok {
+		missingA, missingB := missing(a), missing(b)
+		switch {
+		case missingA && missingB:
+			return 0
+		case missingA:
+			return 1
+		case missingB:
+			return -1
+		}
+	}
+	c := issueComparators[key.Field](a, b, context)
+	if key.Descending {
+		return -c
+	}
+	return c
+}
+
+// compareTime compares optional timestamps, nil sorting last.
+func compareTime(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Compare(*b)
+}
+
+// compareLabelValue compares scoped label values numerically when both are numbers,
+// alphabetically otherwise. Empty values sort last.
+func compareLabelValue(a, b string) int {
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return cmp.Compare(na, nb)
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// dueTime returns the due date of an issue as a time, or nil when unset.
+func dueTime(issue *gitlab.Issue) *time.Time {
+	if issue.DueDate == nil {
+		return nil
+	}
+	t := time.Time(*issue.DueDate)
+	return &t
+}
+
+// scopedLabelValue returns the value of the first label with the given scope prefix,
+// e.g. "1" for "priority::1" with prefix "priority::".
+func scopedLabelValue(labels gitlab.Labels, prefix string) string {
+	for _, label := range labels {
+		if value, ok := strings.CutPrefix(label, prefix); ok {
+			return value
+		}
+	}
+	return ""
+}
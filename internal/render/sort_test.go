@@ -0,0 +1,90 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// issueTitles returns the titles of issues, in order.
+func issueTitles(issues []*gitlab.Issue) []string {
+	titles := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		titles = append(titles, issue.Title)
+	}
+	return titles
+}
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := ParseSortKeys([]string{"due", "updated:desc", " Title:ASC "})
+	if err != nil {
+		t.Fatalf("ParseSortKeys() error = %v", err)
+	}
+	want := []SortKey{{Field: "due"}, {Field: "updated", Descending: true}, {Field: "title"}}
+	if len(keys) != len(want) {
+		t.Fatalf("ParseSortKeys() = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("key[%d] = %v, want %v", i, keys[i], want[i])
+		}
+	}
+
+	for _, spec := range []string{"nope", "title:sideways"} {
+		if _, err := ParseSortKeys([]string{spec}); err == nil {
+			t.Errorf("ParseSortKeys(%q) expected error but got none", spec)
+		}
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	due := gitlab.ISOTime(*day(20))
+	newIssues := func() []*gitlab.Issue {
+		return []*gitlab.Issue{
+			{Title: "b", CreatedAt: day(2), Weight: 1, ProjectID: 2, Labels: gitlab.Labels{"priority::2"}},
+			{Title: "A", CreatedAt: day(3), Weight: 3, ProjectID: 1, DueDate: &due},
+			{Title: "c", CreatedAt: day(1), Weight: 1, ProjectID: 1, Labels: gitlab.Labels{"priority::10"}},
+		}
+	}
+	context := NewGroupContext("g", map[int64]string{1: "g/one", 2: "g/two"})
+
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{name: "no keys keeps API order", keys: nil, want: []string{"b", "A", "c"}},
+		{name: "created ascending", keys: []string{"created"}, want: []string{"c", "b", "A"}},
+		{name: "created descending", keys: []string{"created:desc"}, want: []string{"A", "b", "c"}},
+		{name: "title is case-insensitive", keys: []string{"title"}, want: []string{"A", "b", "c"}},
+		{name: "due date missing last", keys: []string{"due"}, want: []string{"A", "b", "c"}},
+		{name: "due date descending, missing last", keys: []string{"due:desc"}, want: []string{"A", "b", "c"}},
+		{name: "priority numeric, missing last", keys: []string{"priority"}, want: []string{"b", "c", "A"}},
+		{name: "priority descending, missing last", keys: []string{"priority:desc"}, want: []string{"c", "b", "A"}},
+		{name: "closed descending, all missing", keys: []string{"closed:desc"}, want: []string{"b", "A", "c"}},
+		{name: "multi-key", keys: []string{"weight", "created:desc"}, want: []string{"b", "c", "A"}},
+		{name: "project path", keys: []string{"project", "title"}, want: []string{"A", "c", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseSortKeys(tt.keys)
+			if err != nil {
+				t.Fatalf("ParseSortKeys() error = %v", err)
+			}
+			issues := newIssues()
+			SortIssues(issues, keys, context)
+			got := issueTitles(issues)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("SortIssues() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Keys used in summary counts for issues without a value.
+const (
+	noLabelKey    = "(no label)"
+	unassignedKey = "(unassigned)"
+)
+
+// SummaryMode selects whether renderers print the summary block.
+type SummaryMode int
+
+const (
+	// SummaryNone prints the issues only.
+	SummaryNone SummaryMode = iota
+	// SummaryFooter prints the issues followed by the summary block.
+	SummaryFooter
+	// SummaryOnly prints the summary block only.
+	SummaryOnly
+)
+
+// SummaryCount is the number of issues sharing a value.
+type SummaryCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Summary holds aggregate statistics about a list of issues.
+type Summary struct {
+	Total             int            `json:"total"`
+	ByState           []SummaryCount `json:"by_state"`
+	ByLabel           []SummaryCount `json:"by_label"`
+	ByAssignee        []SummaryCount `json:"by_assignee"`
+	ByProject         []SummaryCount `json:"by_project,omitempty"`
+	Overdue           int            `json:"overdue"`
+	MedianOpenAgeDays float64        `json:"median_open_age_days"`
+}
+
+// summaryBuilder accumulates statistics issue by issue, so the summary of a
+// streamed report can be computed without keeping the issues in memory.
+type summaryBuilder struct {
+	context    *Context
+	now        time.Time
+	total      int
+	byState    map[string]int
+	byLabel    map[string]int
+	byAssignee map[string]int
+	byProject  map[string]int
+	overdue    int
+	openAges   []float64
+}
+
+// newSummaryBuilder creates a builder. now is the reference time for ages and overdue issues.
+func newSummaryBuilder(context *Context, now time.Time) *summaryBuilder {
+	return &summaryBuilder{
+		context:    context,
+		now:        now,
+		byState:    make(map[string]int),
+		byLabel:    make(map[string]int),
+		byAssignee: make(map[string]int),
+		byProject:  make(map[string]int),
+	}
+}
+
+// NewSummary computes the summary of issues. now is the reference time for ages
+// and overdue issues.
+func NewSummary(issues []*gitlab.Issue, context *Context, now time.Time) *Summary {
+	b := newSummaryBuilder(context, now)
+	b.add(issues)
+	return b.build()
+}
+
+// add accounts for a page of issues.
+func (b *summaryBuilder) add(issues []*gitlab.Issue) {
+	today := time.Date(b.now.Year(), b.now.Month(), b.now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, issue := range issues {
+		b.total++
+		b.byState[issue.State]++
+		if len(issue.Labels) == 0 {
+			b.byLabel[noLabelKey]++
+		}
+		for _, label := range issue.Labels {
+			b.byLabel[label]++
+		}
+		assigned := false
+		for _, assignee := range issue.Assignees {
+			if assignee != nil {
+				b.byAssignee[assignee.Username]++
+				assigned = true
+			}
+		}
+		if !assigned {
+			b.byAssignee[unassignedKey]++
+		}
+		b.byProject[projectValue(issue, b.context)]++
+
+		if issue.State != "opened" {
+			continue
+		}
+		// Due dates are calendar dates: overdue means due strictly before today
+		if issue.DueDate != nil && time.Time(*issue.DueDate).Before(today) {
+			b.overdue++
+		}
+		if issue.CreatedAt != nil {
+			b.openAges = append(b.openAges, b.now.Sub(*issue.CreatedAt).Hours()/24)
+		}
+	}
+}
+
+// build returns the summary of the issues added so far.
+func (b *summaryBuilder) build() *Summary {
+	s := &Summary{
+		Total:             b.total,
+		ByState:           sortedCounts(b.byState),
+		ByLabel:           sortedCounts(b.byLabel),
+		ByAssignee:        sortedCounts(b.byAssignee),
+		Overdue:           b.overdue,
+		MedianOpenAgeDays: median(b.openAges),
+	}
+	if b.context.spansProjects() {
+		s.ByProject = sortedCounts(b.byProject)
+	}
+	return s
+}
+
+// lines returns the summary as label/value pairs, shared by the text formats.
+func (s *Summary) lines() [][2]string {
+	lines := [][2]string{
+		{"Total", strconv.Itoa(s.Total)},
+		{"By state", formatCounts(s.ByState)},
+		{"By label", formatCounts(s.ByLabel)},
+		{"By assignee", formatCounts(s.ByAssignee)},
+	}
+	if s.ByProject != nil {
+		lines = append(lines, [2]string{"By project", formatCounts(s.ByProject)})
+	}
+	return append(lines,
+		[2]string{"Overdue", strconv.Itoa(s.Overdue)},
+		[2]string{"Median age of open issues", strconv.FormatFloat(s.MedianOpenAgeDays, 'f', -1, 64) + " days"},
+	)
+}
+
+// WriteText writes the summary as an indented plain text block.
+func (s *Summary) WriteText(writer io.Writer) error {
+	if _, err := fmt.Fprintln(writer, "Summary"); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	for _, line := range s.lines() {
+		if _, err := fmt.Fprintf(writer, "  %s: %s\n", line[0], line[1]); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes the summary as a markdown section with a bullet list.
+func (s *Summary) WriteMarkdown(writer io.Writer) error {
+	if _, err := fmt.Fprint(writer, "## Summary\n\n"); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	for _, line := range s.lines() {
+		if _, err := fmt.Fprintf(writer, "- **%s**: %s\n", line[0], escapeMarkdownCell(line[1])); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+	return nil
+}
+
+// records returns the summary as Category/Key/Value records for delimited output.
+func (s *Summary) records() [][]string {
+	records := [][]string{{"Category", "Key", "Value"}, {"total", "", strconv.Itoa(s.Total)}}
+	add := func(category string, counts []SummaryCount) {
+		for _, c := range counts {
+			records = append(records, []string{category, c.Key, strconv.Itoa(c.Count)})
+		}
+	}
+	add("state", s.ByState)
+	add("label", s.ByLabel)
+	add("assignee", s.ByAssignee)
+	add("project", s.ByProject)
+	return append(records,
+		[]string{"overdue", "", strconv.Itoa(s.Overdue)},
+		[]string{"median_open_age_days", "", strconv.FormatFloat(s.MedianOpenAgeDays, 'f', -1, 64)},
+	)
+}
+
+// SummarySetter is implemented by renderers that can print a summary block.
+type SummarySetter interface {
+	SetSummary(mode SummaryMode)
+}
+
+// summarySet holds the summary mode of a renderer. It is embedded by renderers.
+type summarySet struct {
+	summaryMode SummaryMode
+}
+
+// SetSummary selects whether the summary block is printed.
+func (s *summarySet) SetSummary(mode SummaryMode) {
+	s.summaryMode = mode
+}
+
+// showIssues reports whether the issues themselves are printed.
+func (s *summarySet) showIssues() bool {
+	return s.summaryMode != SummaryOnly
+}
+
+// writeSummaryWith calls write when the summary is enabled. In footer mode the
+// summary is separated from the issues by an empty line.
+func (s *summarySet) writeSummaryWith(writer io.Writer, write func() error) error {
+	if s.summaryMode == SummaryNone {
+		return nil
+	}
+	if s.summaryMode == SummaryFooter {
+		if _, err := fmt.Fprintln(writer); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+	return write()
+}
+
+// writeTextSummary writes the plain text summary block when enabled.
+func (s *summarySet) writeTextSummary(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	return s.writeSummaryWith(writer, func() error {
+		return NewSummary(issues, context, time.Now()).WriteText(writer)
+	})
+}
+
+// sortedCounts converts a count map to a slice, most frequent first.
+func sortedCounts(counts map[string]int) []SummaryCount {
+	out := make([]SummaryCount, 0, len(counts))
+	for key, count := range counts {
+		out = append(out, SummaryCount{Key: key, Count: count})
+	}
+	slices.SortFunc(out, func(a, b SummaryCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return out
+}
+
+// formatCounts formats counts as "key count, key count".
+func formatCounts(counts []SummaryCount) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(counts))
+	for _, c := range counts {
+		parts = append(parts, fmt.Sprintf("%s %d", c.Key, c.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// median returns the median of values rounded to one decimal, or 0 when empty.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	m := sorted[mid]
+	if len(sorted)%2 == 0 {
+		m = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return math.Round(m*10) / 10
+}
@@ -0,0 +1,211 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func createSummaryTestIssues(now time.Time) []*gitlab.Issue {
+	daysAgo := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+	yesterday := gitlab.ISOTime(now.AddDate(0, 0, -1))
+	tomorrow := gitlab.ISOTime(now.AddDate(0, 0, 1))
+	return []*gitlab.Issue{
+		{Title: "a", State: "opened", ProjectID: 1, CreatedAt: daysAgo(2), DueDate: &yesterday,
+			Labels: gitlab.Labels{"bug"}, Assignees: []*gitlab.IssueAssignee{{Username: "alice"}}},
+		{Title: "b", State: "opened", ProjectID: 1, CreatedAt: daysAgo(4), DueDate: &tomorrow,
+			Labels: gitlab.Labels{"bug", "backend"}},
+		{Title: "c", State: "opened", ProjectID: 2, CreatedAt: daysAgo(10)},
+		{Title: "d", State: "closed", ProjectID: 2, CreatedAt: daysAgo(30), DueDate: &yesterday,
+			Assignees: []*gitlab.IssueAssignee{{Username: "alice"}, {Username: "bob"}}},
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	context := NewGroupContext("team", map[int64]string{1: "team/api", 2: "team/web"})
+
+	s := NewSummary(createSummaryTestIssues(now), context, now)
+
+	if s.Total != 4 {
+		t.Errorf("Total = %d, want 4", s.Total)
+	}
+	if got := formatCounts(s.ByState); got != "opened 3, closed 1" {
+		t.Errorf("ByState = %q", got)
+	}
+	if got := formatCounts(s.ByLabel); got != "(no label) 2, bug 2, backend 1" {
+		t.Errorf("ByLabel = %q", got)
+	}
+	if got := formatCounts(s.ByAssignee); got != "(unassigned) 2, alice 2, bob 1" {
+		t.Errorf("ByAssignee = %q", got)
+	}
+	if got := formatCounts(s.ByProject); got != "team/api 2, team/web 2" {
+		t.Errorf("ByProject = %q", got)
+	}
+	// Closed issues are never overdue
+	if s.Overdue != 1 {
+		t.Errorf("Overdue = %d, want 1", s.Overdue)
+	}
+	if s.MedianOpenAgeDays != 4 {
+		t.Errorf("MedianOpenAgeDays = %v, want 4", s.MedianOpenAgeDays)
+	}
+
+	if project := NewSummary(nil, NewProjectContext("team/api"), now); project.ByProject != nil {
+		t.Errorf("project summary should not count by project, got %v", project.ByProject)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   float64
+	}{
+		{nil, 0},
+		{[]float64{3}, 3},
+		{[]float64{5, 1, 3}, 3},
+		{[]float64{4, 1, 2, 3}, 2.5},
+		{[]float64{1, 1.33}, 1.2},
+	}
+	for _, tt := range tests {
+		if got := median(tt.values); got != tt.want {
+			t.Errorf("median(%v) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestRenderers_Summary(t *testing.T) {
+	issues := createSummaryTestIssues(time.Now())
+	renderers := map[string]interface {
+		Renderer
+		SummarySetter
+	}{
+		"plain":    NewPlainRenderer(true),
+		"table":    NewTableRenderer(),
+		"markdown": NewMarkdownRenderer(),
+		"csv":      NewCSVRenderer(),
+		"html":     NewHTMLRenderer(),
+	}
+
+	for name, renderer := range renderers {
+		t.Run(name+" footer", func(t *testing.T) {
+			renderer.SetSummary(SummaryFooter)
+			var buf bytes.Buffer
+			if err := renderer.Render(issues, &buf); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			output := buf.String()
+			if !strings.Contains(output, "Summary") && !strings.Contains(output, "median_open_age_days") {
+				t.Errorf("summary block missing:\n%s", output)
+			}
+			if !strings.Contains(output, "a") || !strings.Contains(output, "opened") {
+				t.Errorf("issues missing:\n%s", output)
+			}
+		})
+
+		t.Run(name+" only", func(t *testing.T) {
+			renderer.SetSummary(SummaryOnly)
+			var buf bytes.Buffer
+			if err := renderer.Render(issues, &buf); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if output := buf.String(); strings.Contains(output, "Created At") || strings.Contains(output, "Title") {
+				t.Errorf("summary-only output should not contain the issue table:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestPlainRenderer_SummaryOnly(t *testing.T) {
+	renderer := NewPlainRenderer(true)
+	renderer.SetSummary(SummaryOnly)
+
+	var buf bytes.Buffer
+	context := NewProjectContext("team/api")
+	if err := renderer.RenderWithContext(createSummaryTestIssues(time.Now()), context, &buf); err != nil {
+		t.Fatalf("RenderWithContext() error = %v", err)
+	}
+
+	want := "Project: team/api\n\n" +
+		"Summary\n" +
+		"  Total: 4\n" +
+		"  By state: opened 3, closed 1\n" +
+		"  By label: (no label) 2, bug 2, backend 1\n" +
+		"  By assignee: (unassigned) 2, alice 2, bob 1\n" +
+		"  Overdue: 1\n" +
+		"  Median age of open issues: 4 days\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONRenderer_Summary(t *testing.T) {
+	renderer := NewJSONRenderer()
+	renderer.SetSummary(SummaryOnly)
+
+	var buf bytes.Buffer
+	if err := renderer.Render(createSummaryTestIssues(time.Now()), &buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	var report map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := report["issues"]; ok {
+		t.Error("summary-only report should not contain issues")
+	}
+	summary, ok := report["summary"].(map[string]any)
+	if !ok || summary["total"] != float64(4) || summary["overdue"] != float64(1) {
+		t.Errorf("unexpected summary: %v", report["summary"])
+	}
+}
+
+func TestNDJSONRenderer_SummaryLine(t *testing.T) {
+	renderer := NewNDJSONRenderer()
+	renderer.SetSummary(SummaryFooter)
+	issues := createSummaryTestIssues(time.Now())
+
+	var buf bytes.Buffer
+	for _, page := range [][]*gitlab.Issue{issues[:2], issues[2:]} {
+		if err := renderer.RenderPage(page, nil, &buf); err != nil {
+			t.Fatalf("RenderPage() error = %v", err)
+		}
+	}
+	if err := renderer.Finish(nil, &buf); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 4 issues and 1 summary", len(lines))
+	}
+	var last struct {
+		Summary Summary `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(lines[4]), &last); err != nil {
+		t.Fatalf("invalid summary line: %v", err)
+	}
+	if last.Summary.Total != 4 {
+		t.Errorf("summary total = %d, want 4 across pages", last.Summary.Total)
+	}
+}
+
+func TestTemplateRenderer_Summary(t *testing.T) {
+	renderer, err := NewTemplateRenderer("t", "{{ .Summary.Total }} {{ .Summary.Overdue }}", time.UTC)
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := renderer.Render(createSummaryTestIssues(time.Now()), &buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := buf.String(); got != "4 1" {
+		t.Errorf("output = %q, want %q", got, "4 1")
+	}
+}
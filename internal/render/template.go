@@ -0,0 +1,172 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// TemplateData is the data passed to user-supplied templates.
+type TemplateData struct {
+	Issues      []*gitlab.Issue // Issues to report, already sorted
+	Context     *Context        // Source of the issues, nil when unknown
+	GeneratedAt time.Time       // Time the report was generated, in the report timezone
+	Summary     *Summary        // Aggregate statistics about the issues
+}
+
+// TemplateRenderer renders issues with a user-supplied Go text/template.
+type TemplateRenderer struct {
+	tmpl     *template.Template
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewTemplateRenderer parses a template and creates a new TemplateRenderer.
+// Dates are formatted in location, or in the local timezone when location is nil.
+func NewTemplateRenderer(name, text string, location *time.Location) (*TemplateRenderer, error) {
+	if location == nil {
+		location = time.Local
+	}
+	t := &TemplateRenderer{location: location, now: time.Now}
+	tmpl, err := template.New(name).Funcs(t.funcs(nil)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Render renders issues with the template.
+func (t *TemplateRenderer) Render(issues []*gitlab.Issue, writer io.Writer) error {
+	return t.RenderWithContext(issues, nil, writer)
+}
+
+// RenderWithContext renders issues with the template, exposing the context as .Context.
+func (t *TemplateRenderer) RenderWithContext(issues []*gitlab.Issue, context *Context, writer io.Writer) error {
+	// Functions depending on the context are bound on a copy of the parsed template
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return fmt.Errorf("failed to prepare template: %w", err)
+	}
+	now := t.now()
+	data := TemplateData{
+		Issues:      issues,
+		Context:     context,
+		GeneratedAt: now.In(t.location),
+		Summary:     NewSummary(issues, context, now),
+	}
+	if err := tmpl.Funcs(t.funcs(context)).Execute(writer, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// funcs returns the helper functions available to templates.
+func (t *TemplateRenderer) funcs(context *Context) template.FuncMap {
+	return template.FuncMap{
+		// Dates
+		"date":    t.formatTime,
+		"daysAgo": t.daysAgo,
+		// Labels
+		"hasLabel":     hasLabel,
+		"withLabel":    withLabel,
+		"withoutLabel": withoutLabel,
+		"labelValue":   func(prefix string, issue *gitlab.Issue) string { return scopedLabelValue(issue.Labels, prefix) },
+		"labels":       func(issue *gitlab.Issue) string { return formatLabels(issue.Labels) },
+		// Issue fields
+		"assignees": func(issue *gitlab.Issue) string { return formatAssignees(issue.Assignees) },
+		"project":   func(issue *gitlab.Issue) string { return projectValue(issue, context) },
+		// Collections
+		"count":     func(issues []*gitlab.Issue) int { return len(issues) },
+		"withState": withState,
+		"groupBy": func(field string, issues []*gitlab.Issue) ([]IssueGroup, error) {
+			grouping, err := ParseGroupBy(field)
+			if err != nil {
+				return nil, err
+			}
+			return grouping.Group(issues, context), nil
+		},
+		// Text
+		"pluralize": pluralize,
+		"truncate":  func(length int, s string) string { return truncateStr(s, length) },
+		"mdEscape":  escapeMarkdownCell,
+		"join":      strings.Join,
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+	}
+}
+
+// formatTime formats a *time.Time, time.Time or *gitlab.ISOTime with a Go layout in the
+// report timezone. Unset values format as an empty string.
+func (t *TemplateRenderer) formatTime(layout string, value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case time.Time:
+		return v.In(t.location).Format(layout), nil
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		return v.In(t.location).Format(layout), nil
+	case *gitlab.ISOTime:
+		if v == nil {
+			return "", nil
+		}
+		// Due dates are calendar dates without a timezone
+		return time.Time(*v).Format(layout), nil
+	default:
+		return "", fmt.Errorf("date: unsupported value of type %T", value)
+	}
+}
+
+// daysAgo returns the number of whole days elapsed since a timestamp, or 0 when unset.
+func (t *TemplateRenderer) daysAgo(value *time.Time) int {
+	if value == nil {
+		return 0
+	}
+	return int(t.now().Sub(*value).Hours() / 24)
+}
+
+// hasLabel reports whether an issue has the given label.
+func hasLabel(label string, issue *gitlab.Issue) bool {
+	return slices.Contains(issue.Labels, label)
+}
+
+// withLabel returns the issues having the given label.
+func withLabel(label string, issues []*gitlab.Issue) []*gitlab.Issue {
+	var out []*gitlab.Issue
+	for _, issue := range issues {
+		if hasLabel(label, issue) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// withoutLabel returns the issues not having the given label.
+func withoutLabel(label string, issues []*gitlab.Issue) []*gitlab.Issue {
+	var out []*gitlab.Issue
+	for _, issue := range issues {
+		if !hasLabel(label, issue) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// withState returns the issues in the given state.
+func withState(state string, issues []*gitlab.Issue) []*gitlab.Issue {
+	var out []*gitlab.Issue
+	for _, issue := range issues {
+		if issue.State == state {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
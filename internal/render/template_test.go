@@ -0,0 +1,122 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// renderTemplate renders issues with a template and returns the output.
+func renderTemplate(t *testing.T, text string, issues []*gitlab.Issue, context *Context) string {
+	t.Helper()
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	renderer, err := NewTemplateRenderer("test", text, location)
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+	renderer.now = func() time.Time { return time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC) }
+
+	var buf bytes.Buffer
+	if err := renderer.RenderWithContext(issues, context, &buf); err != nil {
+		t.Fatalf("RenderWithContext() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestNewTemplateRenderer_ParseError(t *testing.T) {
+	if _, err := NewTemplateRenderer("bad", "{{ range .Issues }}", nil); err == nil {
+		t.Error("NewTemplateRenderer() expected error for unterminated range")
+	}
+	if _, err := NewTemplateRenderer("bad", "{{ nope .Issues }}", nil); err == nil {
+		t.Error("NewTemplateRenderer() expected error for unknown function")
+	}
+}
+
+func TestTemplateRenderer_Helpers(t *testing.T) {
+	created := time.Date(2024, 1, 10, 3, 0, 0, 0, time.UTC)
+	issues := createTestIssuesWithProjects()
+	issues[0].CreatedAt = &created
+	issues[0].Title = "Fix | pipes"
+	issues[0].Assignees = []*gitlab.IssueAssignee{{Username: "bob"}}
+	issues[0].Labels = gitlab.Labels{"bug", "priority::1"}
+	context := NewGroupContext("my-group", map[int64]string{100: "ns/a", 200: "ns/b"})
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "context and count",
+			text: `{{ .Context.GroupPath }}: {{ count .Issues }} {{ pluralize (count .Issues) "issue" "issues" }}`,
+			want: "my-group: 3 3 issues",
+		},
+		{
+			name: "date in report timezone",
+			text: `{{ date "2006-01-02 15:04" (index .Issues 0).CreatedAt }}`,
+			want: "2024-01-09 22:00",
+		},
+		{
+			name: "generated at",
+			text: `{{ date "2006-01-02T15:04" .GeneratedAt }}`,
+			want: "2024-01-15T07:00",
+		},
+		{
+			name: "days ago",
+			text: `{{ daysAgo (index .Issues 0).CreatedAt }}`,
+			want: "5",
+		},
+		{
+			name: "label filtering",
+			text: `{{ range withLabel "bug" .Issues }}{{ .Title }};{{ end }}{{ len (withoutLabel "bug" .Issues) }}`,
+			want: "Fix | pipes;2",
+		},
+		{
+			name: "scoped label value",
+			text: `{{ labelValue "priority::" (index .Issues 0) }}`,
+			want: "1",
+		},
+		{
+			name: "state filtering",
+			text: `{{ count (withState "opened" .Issues) }}`,
+			want: "2",
+		},
+		{
+			name: "grouping",
+			text: `{{ range groupBy "project" .Issues }}{{ .Key }}={{ len .Issues }} {{ end }}`,
+			want: "ns/a=2 ns/b=1 ",
+		},
+		{
+			name: "issue helpers",
+			text: `{{ with index .Issues 0 }}{{ project . }} {{ assignees . }} {{ labels . }}{{ end }}`,
+			want: "ns/a bob bug, priority::1",
+		},
+		{
+			name: "text helpers",
+			text: `{{ mdEscape (index .Issues 0).Title }} {{ truncate 3 "abcdef" }} {{ upper "x" }}`,
+			want: `Fix \| pipes abc X`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderTemplate(t, tt.text, issues, context); got != tt.want {
+				t.Errorf("template output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateRenderer_WithoutContext(t *testing.T) {
+	output := renderTemplate(t, `{{ if .Context }}ctx{{ else }}none{{ end }} {{ range .Issues }}{{ .State }} {{ end }}`,
+		createTestIssues(), nil)
+	if !strings.HasPrefix(output, "none opened closed opened") {
+		t.Errorf("unexpected output %q", output)
+	}
+}